@@ -0,0 +1,92 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/awesome-gocui/gocui"
+)
+
+var commands = []string{
+	"add", "bisect", "branch", "checkout", "cherry-pick", "clone", "commit",
+	"config", "diff", "fetch", "grep", "init", "log", "merge", "pull", "push",
+	"rebase", "reset", "restore", "show", "stash", "status", "switch", "tag",
+}
+
+func main() {
+	g, err := gocui.NewGui(gocui.OutputNormal, true)
+	if err != nil {
+		log.Panicln(err)
+	}
+	defer g.Close()
+
+	g.Cursor = true
+
+	g.SetManagerFunc(func(g *gocui.Gui) error {
+		maxX, maxY := g.Size()
+		if v, err := g.SetView("output", 0, 0, maxX-1, maxY-4, 0); err != nil {
+			if !errors.Is(err, gocui.ErrUnknownView) {
+				return err
+			}
+			v.Autoscroll = true
+		}
+		if v, err := g.SetView("prompt", 0, maxY-3, maxX-1, maxY-1, 0); err != nil {
+			if !errors.Is(err, gocui.ErrUnknownView) {
+				return err
+			}
+			v.Title = "Tab: complete"
+			v.Editable = true
+			v.SingleLine = true
+			v.Placeholder = "Type a git command"
+			e := gocui.NewCompletionEditor(gocui.CompleterFunc(complete))
+			e.Auto = true
+			v.Editor = e
+			v.OnSubmit = submit
+			if _, err := g.SetCurrentView("prompt"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, quit); err != nil {
+		log.Panicln(err)
+	}
+
+	if err := g.MainLoop(); err != nil && !errors.Is(err, gocui.ErrQuit) {
+		log.Panicln(err)
+	}
+}
+
+// complete completes the first word with the commands.
+func complete(buffer string, x, y int) []string {
+	for i, r := range []rune(buffer) {
+		if i >= x {
+			break
+		}
+		if r == ' ' {
+			return nil
+		}
+	}
+	return commands
+}
+
+// submit echoes the command.
+func submit(g *gocui.Gui, v *gocui.View) error {
+	output, err := g.View("output")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(output, "$ git %s\n", v.Buffer())
+	v.Clear()
+	return nil
+}
+
+func quit(g *gocui.Gui, v *gocui.View) error {
+	return gocui.ErrQuit
+}
@@ -53,7 +53,8 @@ func layout(g *gocui.Gui) error {
 		v.Title = "v1"
 		v.Autoscroll = true
 		v.PaddingX = 1
-		fmt.Fprintln(v, "View with default frame color")
+		v.SelFrameRunes = gocui.FrameRunesHeavy()
+		fmt.Fprintln(v, "View with default frame color and a heavy frame when focused")
 		fmt.Fprintln(v, "It's connected to v2 with overlay RIGHT.\n")
 		if _, err = setCurrentViewOnTop(g, "v1"); err != nil {
 			return err
@@ -99,7 +100,7 @@ func layout(g *gocui.Gui) error {
 		v.Editable = true
 		v.TitleColor = gocui.ColorYellow
 		v.FrameColor = gocui.ColorRed
-		v.FrameRunes = []rune{'═', '║', '╔', '╗', '╚', '╝', '╠', '╣', '╦', '╩', '╬'}
+		v.FrameRunes = gocui.FrameRunesDouble()
 		v.PaddingX = 1
 		fmt.Fprintln(v, "View with fully customized frame and colored title differently.")
 		fmt.Fprintln(v, "It's connected to v3 with overlay LEFT.\n")
@@ -0,0 +1,81 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/awesome-gocui/gocui"
+)
+
+func required(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return errors.New("required")
+	}
+	return nil
+}
+
+func main() {
+	g, err := gocui.NewGui(gocui.OutputNormal, true)
+	if err != nil {
+		log.Panicln(err)
+	}
+	defer g.Close()
+
+	g.Mouse = true
+	g.Cursor = true
+
+	form := gocui.NewForm("signup", 0, 0, 40, 30,
+		&gocui.FormField{Name: "user", Label: "User name", Validate: required},
+		&gocui.FormField{Name: "password", Label: "Password", Kind: gocui.FieldPassword, Validate: required},
+		&gocui.FormField{Name: "age", Label: "Age", Kind: gocui.FieldNumber},
+		&gocui.FormField{Name: "plan", Label: "Plan", Kind: gocui.FieldRadio, Options: []string{"Free", "Pro", "Team"}},
+		&gocui.FormField{Name: "country", Label: "Country", Kind: gocui.FieldDropdown, Options: []string{"France", "Germany", "Japan", "USA"}},
+		&gocui.FormField{Name: "terms", Label: "I accept the terms", Kind: gocui.FieldCheckbox, Validate: func(value string) error {
+			if value != "true" {
+				return errors.New("must be accepted")
+			}
+			return nil
+		}},
+	)
+	form.OnSubmit = func(g *gocui.Gui, f *gocui.Form) error {
+		values := f.Values()
+		delete(values, "password")
+		_, err := g.Alert("Submitted", fmt.Sprint(values), func(g *gocui.Gui) error {
+			return gocui.ErrQuit
+		})
+		return err
+	}
+	form.OnCancel = quit
+
+	g.SetManagerFunc(func(g *gocui.Gui) error {
+		maxX, maxY := g.Size()
+		form.SetDimensions(maxX/2-20, 0, maxX/2+20, maxY-1)
+		if err := form.Layout(g); err != nil {
+			return err
+		}
+		if g.CurrentView() == nil {
+			return form.Focus(g)
+		}
+		return nil
+	})
+
+	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
+		return gocui.ErrQuit
+	}); err != nil {
+		log.Panicln(err)
+	}
+
+	if err := g.MainLoop(); err != nil && !errors.Is(err, gocui.ErrQuit) {
+		log.Panicln(err)
+	}
+}
+
+func quit(g *gocui.Gui, f *gocui.Form) error {
+	return gocui.ErrQuit
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/awesome-gocui/gocui"
+)
+
+func main() {
+	g, err := gocui.NewGui(gocui.OutputNormal, true)
+	if err != nil {
+		log.Panicln(err)
+	}
+	defer g.Close()
+
+	g.Cursor = true
+
+	history, err := gocui.LoadHistory(filepath.Join(os.TempDir(), "gocui-history"), 100)
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	g.SetManagerFunc(func(g *gocui.Gui) error {
+		maxX, maxY := g.Size()
+		if v, err := g.SetView("output", 0, 0, maxX-1, maxY-4, 0); err != nil {
+			if !errors.Is(err, gocui.ErrUnknownView) {
+				return err
+			}
+			v.Autoscroll = true
+		}
+		if v, err := g.SetView("prompt", 0, maxY-3, maxX-1, maxY-1, 0); err != nil {
+			if !errors.Is(err, gocui.ErrUnknownView) {
+				return err
+			}
+			v.Title = "Up/Down: history, Ctrl+R: search"
+			v.Editable = true
+			v.SingleLine = true
+			v.Placeholder = "Type a command"
+			v.Editor = gocui.NewHistoryEditor(history)
+			v.OnSubmit = submit
+			if _, err := g.SetCurrentView("prompt"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, quit); err != nil {
+		log.Panicln(err)
+	}
+
+	if err := g.MainLoop(); err != nil && !errors.Is(err, gocui.ErrQuit) {
+		log.Panicln(err)
+	}
+}
+
+// submit echoes the command, and adds it to the history.
+func submit(g *gocui.Gui, v *gocui.View) error {
+	command := v.Buffer()
+	output, err := g.View("output")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(output, "> %s\n", command)
+	v.Clear()
+	return v.Editor.(*gocui.HistoryEditor).History.Add(command)
+}
+
+func quit(g *gocui.Gui, v *gocui.View) error {
+	return gocui.ErrQuit
+}
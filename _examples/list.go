@@ -0,0 +1,76 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/awesome-gocui/gocui"
+)
+
+func main() {
+	g, err := gocui.NewGui(gocui.OutputNormal, true)
+	if err != nil {
+		log.Panicln(err)
+	}
+	defer g.Close()
+
+	g.Mouse = true
+
+	var items []gocui.ListItem
+	for _, fruit := range []string{"Apple", "Apricot", "Banana", "Blackberry", "Cherry", "Grape", "Lemon", "Mango", "Orange", "Peach", "Pear", "Plum"} {
+		items = append(items, gocui.ListItem{Text: fruit})
+	}
+	list := gocui.NewList("list", 0, 0, 30, 10, items...)
+	list.MultiSelect = true
+	list.OnSubmit = func(g *gocui.Gui, l *gocui.List) error {
+		v, err := g.View("status")
+		if err != nil {
+			return err
+		}
+		var names []string
+		for _, i := range l.Marked() {
+			names = append(names, l.Items()[i].Text)
+		}
+		v.Clear()
+		fmt.Fprintf(v, "Marked: %s", strings.Join(names, ", "))
+		return nil
+	}
+
+	g.SetManagerFunc(func(g *gocui.Gui) error {
+		maxX, maxY := g.Size()
+		list.SetDimensions(0, 0, maxX/2, maxY-4)
+		if err := list.Layout(g); err != nil {
+			return err
+		}
+		if v, err := g.SetView("status", 0, maxY-3, maxX-1, maxY-1, 0); err != nil {
+			if !errors.Is(err, gocui.ErrUnknownView) {
+				return err
+			}
+			v.Title = "Type to filter, Space: mark, Enter: submit"
+		}
+		if g.CurrentView() == nil {
+			if _, err := g.SetCurrentView("list"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, quit); err != nil {
+		log.Panicln(err)
+	}
+
+	if err := g.MainLoop(); err != nil && !errors.Is(err, gocui.ErrQuit) {
+		log.Panicln(err)
+	}
+}
+
+func quit(g *gocui.Gui, v *gocui.View) error {
+	return gocui.ErrQuit
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/awesome-gocui/gocui"
+)
+
+func main() {
+	g, err := gocui.NewGui(gocui.OutputNormal, true)
+	if err != nil {
+		log.Panicln(err)
+	}
+	defer g.Close()
+
+	g.Cursor = true
+	g.ModalDim = true
+	g.SetManagerFunc(layout)
+
+	if err := g.SetKeybinding("main", 'n', gocui.ModNone, rename); err != nil {
+		log.Panicln(err)
+	}
+	if err := g.SetKeybinding("main", 'q', gocui.ModNone, confirmQuit); err != nil {
+		log.Panicln(err)
+	}
+	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, quit); err != nil {
+		log.Panicln(err)
+	}
+
+	if err := g.MainLoop(); err != nil && !errors.Is(err, gocui.ErrQuit) {
+		log.Panicln(err)
+	}
+}
+
+func layout(g *gocui.Gui) error {
+	maxX, maxY := g.Size()
+	if v, err := g.SetView("main", 0, 0, maxX-1, maxY-1, 0); err != nil {
+		if !errors.Is(err, gocui.ErrUnknownView) {
+			return err
+		}
+		v.Title = "Hello world"
+		fmt.Fprintln(v, "n: rename the view")
+		fmt.Fprintln(v, "q: quit")
+
+		if _, err := g.SetCurrentView("main"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func rename(g *gocui.Gui, v *gocui.View) error {
+	_, err := g.Prompt("New title", v.Title, func(g *gocui.Gui, text string, ok bool) error {
+		if !ok {
+			return nil
+		}
+		if text == "" {
+			_, err := g.Alert("Error", "The title can't be empty.", nil)
+			return err
+		}
+		v.Title = text
+		return nil
+	})
+	return err
+}
+
+func confirmQuit(g *gocui.Gui, v *gocui.View) error {
+	_, err := g.Confirm("Quit", "Do you really want to quit?", func(g *gocui.Gui, ok bool) error {
+		if ok {
+			return gocui.ErrQuit
+		}
+		return nil
+	})
+	return err
+}
+
+func quit(g *gocui.Gui, v *gocui.View) error {
+	return gocui.ErrQuit
+}
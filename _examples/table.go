@@ -6,90 +6,67 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"log"
 
 	"github.com/awesome-gocui/gocui"
 )
 
-type Column struct {
-	Title string
-	Size  float32
-}
-
-type Table struct {
-	name          string
-	Left, Top     int
-	Right, Bottom int
-	Columns       []Column
-	Data          [][]string
-}
-
-func NewTable(name string, left, top, right, bottom int) *Table {
-	return &Table{
-		name:   name,
-		Left:   left,
-		Top:    top,
-		Right:  right,
-		Bottom: bottom,
+func main() {
+	g, err := gocui.NewGui(gocui.OutputNormal, true)
+	if err != nil {
+		log.Panicln(err)
 	}
-}
+	defer g.Close()
 
-func (t *Table) Layout(g *gocui.Gui) error {
-	view, err := g.SetView(t.name, t.Left, t.Top, t.Right, t.Bottom, 0)
-	if err != nil && !errors.Is(err, gocui.ErrUnknownView) {
-		return err
+	g.Mouse = true
+
+	table := gocui.NewTable("t", 0, 0, 80, 10,
+		gocui.TableColumn{Title: "Name", Sortable: true},
+		gocui.TableColumn{Title: "Size", Align: gocui.AlignRight, Sortable: true},
+		gocui.TableColumn{Title: "Owner", Policy: gocui.WidthFixed, Width: 10},
+		gocui.TableColumn{Title: "Description", Policy: gocui.WidthFill, MinWidth: 30},
+	)
+	table.Frozen = 1
+	for i := 1; i <= 50; i++ {
+		row := gocui.TableRow(fmt.Sprintf("file%02d.txt", i), fmt.Sprint(i*i*37%1000), "gocui", fmt.Sprintf("File number %d, with a description which may not fit", i))
+		if i%7 == 0 {
+			row[1].FgColor = gocui.ColorRed
+		}
+		table.AddRow(row...)
 	}
 
-	width, height := view.Size()
-	hOffset := 0
-	for cid, column := range t.Columns {
-		size := int(float32(width+1) * column.Size)
-
-		view.SetWritePos(hOffset, 0)
-		view.WriteString(column.Title)
-
-		for rid := 0; rid < height; rid++ {
-			if rid < len(t.Data[cid]) {
-				view.SetWritePos(hOffset, rid+1)
-				view.WriteString(t.Data[cid][rid])
+	g.SetManagerFunc(func(g *gocui.Gui) error {
+		maxX, maxY := g.Size()
+		table.SetDimensions(0, 0, maxX-1, maxY-4)
+		if err := table.Layout(g); err != nil {
+			return err
+		}
+		if v, err := g.SetView("help", 0, maxY-3, maxX-1, maxY-1, 0); err != nil {
+			if !errors.Is(err, gocui.ErrUnknownView) {
+				return err
 			}
-			view.SetWritePos(hOffset+size-1, rid)
-			view.WriteRunes([]rune{'│'})
+			fmt.Fprint(v, "Arrows: move and scroll, click on a title: sort, c: toggle cell selection")
 		}
+		if g.CurrentView() == nil {
+			if _, err := g.SetCurrentView("t"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 
-		hOffset += size
-	}
-
-	return nil
-}
-
-func main() {
-	g, err := gocui.NewGui(gocui.OutputNormal, false)
-	if err != nil {
+	if err := g.SetKeybinding("", 'c', gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
+		table.CellSelect = !table.CellSelect
+		return nil
+	}); err != nil {
 		log.Panicln(err)
 	}
-	defer g.Close()
-
-	table := NewTable("t", 0, 2, 80, 10)
-	table.Columns = []Column{
-		{"Column1", 0.25},
-		{"Column2", 0.25},
-		{"Column3", 0.25},
-		{"Column4", 0.25},
-	}
-	table.Data = [][]string{
-		{"00", "01", "02", "03"},
-		{"10", "11", "12", "13"},
-		{"20", "21", "22", "23"},
-		{"30", "31", "32", "33"},
-	}
-	g.SetManager(table)
-
 	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, quit); err != nil {
 		log.Panicln(err)
 	}
 
-	if err := g.MainLoop(); err != nil && err != gocui.ErrQuit {
+	if err := g.MainLoop(); err != nil && !errors.Is(err, gocui.ErrQuit) {
 		log.Panicln(err)
 	}
 }
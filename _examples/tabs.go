@@ -0,0 +1,73 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/awesome-gocui/gocui"
+)
+
+func main() {
+	g, err := gocui.NewGui(gocui.OutputNormal, true)
+	if err != nil {
+		log.Panicln(err)
+	}
+	defer g.Close()
+
+	g.Mouse = true
+
+	tabs := gocui.NewTabs("tabs", 0, 0, 40, 10,
+		gocui.Tab{Name: "readme", Label: "README"},
+		gocui.Tab{Name: "log", Label: "Log", Closable: true},
+		gocui.Tab{Name: "help", Label: "Help", Closable: true},
+	)
+	// the tabs are switched with Tab and Shift+Tab, instead of Alt+PgDn
+	// and Alt+PgUp
+	tabs.NextKey, tabs.PrevKey, tabs.KeyMod = gocui.KeyTab, gocui.KeyBacktab, gocui.ModNone
+	tabs.OnClose = func(g *gocui.Gui, t gocui.Tab) error {
+		if len(tabs.Tabs()) == 0 {
+			return gocui.ErrQuit
+		}
+		return nil
+	}
+
+	g.SetManagerFunc(func(g *gocui.Gui) error {
+		maxX, maxY := g.Size()
+		tabs.SetDimensions(0, 0, maxX-1, maxY-1)
+		if err := tabs.Layout(g); err != nil {
+			return err
+		}
+		for _, t := range tabs.Tabs() {
+			v, err := g.View(t.Name)
+			if err != nil || v.Buffer() != "" {
+				continue
+			}
+			fmt.Fprintf(v, "This is the %s tab.\nTab / Shift+Tab: switch tabs\nClick on a label to switch, on × to close.\n", t.Label)
+		}
+		if g.CurrentView() == nil {
+			if t, ok := tabs.Active(); ok {
+				if _, err := g.SetCurrentView(t.Name); err != nil && !errors.Is(err, gocui.ErrUnknownView) {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+
+	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, quit); err != nil {
+		log.Panicln(err)
+	}
+
+	if err := g.MainLoop(); err != nil && !errors.Is(err, gocui.ErrQuit) {
+		log.Panicln(err)
+	}
+}
+
+func quit(g *gocui.Gui, v *gocui.View) error {
+	return gocui.ErrQuit
+}
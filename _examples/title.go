@@ -111,6 +111,26 @@ func layout(g *gocui.Gui) error {
 		}
 	}
 
+	// Aligned and styled labels
+	if v, err := g.SetView("v19", 10, 36, 50, 40, 0); err != nil {
+		if !errors.Is(err, gocui.ErrUnknownView) {
+			return err
+		}
+		v.Title = "Centered title"
+		v.TitleAlign = gocui.AlignCenter
+		v.Labels = []gocui.FrameLabel{
+			{
+				Spans: []gocui.LabelSpan{
+					{Text: "status: ", FgColor: gocui.ColorDefault, BgColor: gocui.ColorDefault},
+					{Text: "OK", FgColor: gocui.ColorGreen | gocui.AttrBold, BgColor: gocui.ColorDefault},
+				},
+				Edge:  gocui.EdgeBottom,
+				Align: gocui.AlignLeft,
+			},
+			gocui.NewFrameLabel(gocui.EdgeBottom, gocui.AlignRight, "q: quit"),
+		}
+	}
+
 	// Small view
 	if v, err := g.SetView("v8", 85, 12, 88, 16, 0); err != nil {
 		if !errors.Is(err, gocui.ErrUnknownView) {
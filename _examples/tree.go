@@ -0,0 +1,97 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/awesome-gocui/gocui"
+)
+
+// dirNode returns a node for the file at path, whose children are loaded
+// when it is expanded if it is a directory.
+func dirNode(path string, info os.FileInfo) *gocui.TreeNode {
+	return &gocui.TreeNode{Text: info.Name(), Value: path, Lazy: info.IsDir()}
+}
+
+// loadDir returns the nodes of the files in the directory of n.
+func loadDir(n *gocui.TreeNode) ([]*gocui.TreeNode, error) {
+	path := n.Value.(string)
+	infos, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*gocui.TreeNode, len(infos))
+	for i, info := range infos {
+		nodes[i] = dirNode(filepath.Join(path, info.Name()), info)
+	}
+	return nodes, nil
+}
+
+func main() {
+	g, err := gocui.NewGui(gocui.OutputNormal, true)
+	if err != nil {
+		log.Panicln(err)
+	}
+	defer g.Close()
+
+	g.Mouse = true
+
+	wd, err := os.Getwd()
+	if err != nil {
+		log.Panicln(err)
+	}
+	root := &gocui.TreeNode{Text: wd, Value: wd, Lazy: true, Expanded: true}
+	tree := gocui.NewTree("tree", 0, 0, 40, 20, root)
+	tree.Load = loadDir
+	tree.OnChange = func(g *gocui.Gui, t *gocui.Tree) error {
+		v, err := g.View("path")
+		if err != nil {
+			return err
+		}
+		v.Clear()
+		if n := t.Selected(); n != nil {
+			fmt.Fprint(v, n.Value)
+		}
+		return nil
+	}
+
+	g.SetManagerFunc(func(g *gocui.Gui) error {
+		maxX, maxY := g.Size()
+		if v, err := g.SetView("path", 0, maxY-3, maxX-1, maxY-1, 0); err != nil {
+			if !errors.Is(err, gocui.ErrUnknownView) {
+				return err
+			}
+			v.Title = "Space: expand/collapse, type to filter"
+		}
+		tree.SetDimensions(0, 0, maxX-1, maxY-4)
+		if err := tree.Layout(g); err != nil {
+			return err
+		}
+		if g.CurrentView() == nil {
+			if _, err := g.SetCurrentView("tree"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, quit); err != nil {
+		log.Panicln(err)
+	}
+
+	if err := g.MainLoop(); err != nil && !errors.Is(err, gocui.ErrQuit) {
+		log.Panicln(err)
+	}
+}
+
+func quit(g *gocui.Gui, v *gocui.View) error {
+	return gocui.ErrQuit
+}
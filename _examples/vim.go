@@ -0,0 +1,59 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/awesome-gocui/gocui"
+)
+
+func main() {
+	g, err := gocui.NewGui(gocui.OutputNormal, true)
+	if err != nil {
+		log.Panicln(err)
+	}
+	defer g.Close()
+
+	g.Cursor = true
+
+	g.SetManagerFunc(func(g *gocui.Gui) error {
+		maxX, maxY := g.Size()
+		if v, err := g.SetView("editor", 0, 0, maxX-1, maxY-1, 0); err != nil {
+			if !errors.Is(err, gocui.ErrUnknownView) {
+				return err
+			}
+			v.Title = "Ctrl+C: quit"
+			v.Subtitle = gocui.VimNormal.String()
+			v.Editable = true
+			v.LineNumbers = gocui.LineNumbersAbsolute
+			e := gocui.NewVimEditor()
+			e.OnModeChange = func(v *gocui.View, mode gocui.VimMode) {
+				v.Subtitle = mode.String()
+			}
+			v.Editor = e
+			fmt.Fprintln(v, "Press i to insert text, and Esc to return to the normal mode.")
+			fmt.Fprintln(v, "Try dw, ci( or yyp (like this).")
+			if _, err := g.SetCurrentView("editor"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, quit); err != nil {
+		log.Panicln(err)
+	}
+
+	if err := g.MainLoop(); err != nil && !errors.Is(err, gocui.ErrQuit) {
+		log.Panicln(err)
+	}
+}
+
+func quit(g *gocui.Gui, v *gocui.View) error {
+	return gocui.ErrQuit
+}
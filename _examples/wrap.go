@@ -20,6 +20,8 @@ func layout(g *gocui.Gui) error {
 			return err
 		}
 		v.Wrap = true
+		v.WordWrap = true
+		v.WrapIndicator = "↪ "
 
 		line := strings.Repeat("This is a long line -- ", 10)
 		fmt.Fprintf(v, "%s\n\n", line)
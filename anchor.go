@@ -0,0 +1,175 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+// Placement is the side of its anchor where a view is placed.
+type Placement int
+
+// Placements of anchored views.
+const (
+	PlaceBelow Placement = iota
+	PlaceAbove
+	PlaceRight
+	PlaceLeft
+)
+
+// Anchor describes the position of a view relative to another view, to the
+// cursor of another view, or to a cell of the screen. Anchored views are
+// placed at each layout, after the managers ran: a popup follows its anchor
+// and is flipped to the opposite side when it doesn't fit on the screen.
+type Anchor struct {
+	// View is the name of the view the view is anchored to. If it is
+	// empty, the view is anchored to the cell (X, Y) of the screen.
+	View string
+
+	// If Cursor is true, the view is anchored to the cursor of View
+	// instead of its frame.
+	Cursor bool
+
+	// X and Y are the screen coordinates of the cell the view is anchored
+	// to when View is empty.
+	X, Y int
+
+	// Placement is the preferred side of the anchor.
+	Placement Placement
+
+	// Width and Height are the size of the content of the view. The view
+	// is shrunk if it doesn't fit on the screen.
+	Width, Height int
+}
+
+// SetAnchor anchors the view with the given name, and places it. It is
+// placed again at each layout, until ClearAnchor is called.
+func (g *Gui) SetAnchor(name string, a Anchor) error {
+	v, err := g.View(name)
+	if err != nil {
+		return err
+	}
+	v.anchor = &a
+	g.placeAnchored(v)
+	return nil
+}
+
+// ClearAnchor removes the anchor of the view with the given name. The view
+// keeps its last position.
+func (g *Gui) ClearAnchor(name string) error {
+	v, err := g.View(name)
+	if err != nil {
+		return err
+	}
+	v.anchor = nil
+	return nil
+}
+
+// layoutAnchors places the anchored views.
+func (g *Gui) layoutAnchors() {
+	for _, v := range g.views {
+		if v.anchor != nil {
+			g.placeAnchored(v)
+		}
+	}
+}
+
+// placeAnchored places v next to its anchor. v is left in place if the
+// view it is anchored to doesn't exist anymore.
+func (g *Gui) placeAnchored(v *View) {
+	a := v.anchor
+	r := anchorRect{a.X, a.Y, a.X, a.Y, true}
+	if a.View != "" {
+		av, err := g.View(a.View)
+		if err != nil {
+			return
+		}
+		if a.Cursor {
+			x, y, _ := av.cursorScreenPosition()
+			r = anchorRect{x, y, x, y, true}
+		} else {
+			r = anchorRect{av.x0, av.y0, av.x1, av.y1, false}
+		}
+	}
+
+	x0, y0, x1, y1 := r.place(a.Placement, a.Width+2, a.Height+2, g.maxX, g.maxY)
+	if x0 != v.x0 || y0 != v.y0 || x1 != v.x1 || y1 != v.y1 {
+		v.x0, v.y0, v.x1, v.y1 = x0, y0, x1, y1
+		v.tainted = true
+	}
+}
+
+// anchorRect is the rectangle a view is placed next to.
+type anchorRect struct {
+	x0, y0, x1, y1 int
+
+	// cell is true if the rectangle is a cell, the content of the view is
+	// then aligned with the cell instead of its frame
+	cell bool
+}
+
+// place returns the position of a view of w by h cells, including its
+// frame, placed next to r on a screen of maxX by maxY cells. The view is
+// flipped to the opposite side if it fits better there, then shrunk and
+// moved to fit on the screen.
+func (r anchorRect) place(p Placement, w, h, maxX, maxY int) (x0, y0, x1, y1 int) {
+	align := 0
+	if r.cell {
+		align = 1 // the frame is before the cell
+	}
+
+	switch p {
+	case PlaceBelow, PlaceAbove:
+		y0, h = placeAxis(r.y0, r.y1, h, maxY, p == PlaceBelow)
+		x0 = r.x0 - align
+	default:
+		x0, w = placeAxis(r.x0, r.x1, w, maxX, p == PlaceRight)
+		y0 = r.y0 - align
+	}
+
+	w, h = clampSize(w, maxX), clampSize(h, maxY)
+	x0, y0 = clampStart(x0, w, maxX), clampStart(y0, h, maxY)
+	return x0, y0, x0 + w - 1, y0 + h - 1
+}
+
+// placeAxis places a segment of n cells before or after the segment from
+// start to end (included) on an axis of max cells. It is flipped to the
+// other side if it doesn't fit and the other side has more room, and it is
+// shrunk to the room available.
+func placeAxis(start, end, n, max int, after bool) (pos, size int) {
+	roomBefore, roomAfter := start, max-end-1
+	if after && n > roomAfter && (n <= roomBefore || roomBefore > roomAfter) {
+		after = false
+	} else if !after && n > roomBefore && (n <= roomAfter || roomAfter > roomBefore) {
+		after = true
+	}
+
+	if after {
+		if n > roomAfter && roomAfter > 0 {
+			n = roomAfter
+		}
+		return end + 1, n
+	}
+	if n > roomBefore && roomBefore > 0 {
+		n = roomBefore
+	}
+	return start - n, n
+}
+
+// clampSize returns n, reduced to max if it is greater.
+func clampSize(n, max int) int {
+	if n > max {
+		return max
+	}
+	return n
+}
+
+// clampStart returns the start of a segment of n cells moved to fit
+// between 0 and max.
+func clampStart(pos, n, max int) int {
+	if pos+n > max {
+		pos = max - n
+	}
+	if pos < 0 {
+		pos = 0
+	}
+	return pos
+}
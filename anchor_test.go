@@ -0,0 +1,33 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"testing"
+)
+
+func TestAnchorPlace(t *testing.T) {
+	tests := []struct {
+		name  string
+		r     anchorRect
+		p     Placement
+		w, h  int
+		place [4]int
+	}{
+		{"below a cell", anchorRect{10, 5, 10, 5, true}, PlaceBelow, 12, 5, [4]int{9, 6, 20, 10}},
+		{"flipped above", anchorRect{10, 20, 10, 20, true}, PlaceBelow, 12, 6, [4]int{9, 14, 20, 19}},
+		{"clamped to the right edge", anchorRect{75, 5, 75, 5, true}, PlaceBelow, 12, 5, [4]int{68, 6, 79, 10}},
+		{"right of a view", anchorRect{10, 2, 30, 8, false}, PlaceRight, 20, 5, [4]int{31, 2, 50, 6}},
+		{"flipped left", anchorRect{60, 2, 75, 8, false}, PlaceRight, 20, 5, [4]int{40, 2, 59, 6}},
+		{"shrunk on the largest side", anchorRect{10, 12, 10, 12, true}, PlaceBelow, 12, 20, [4]int{9, 0, 20, 11}},
+	}
+
+	for _, tt := range tests {
+		x0, y0, x1, y1 := tt.r.place(tt.p, tt.w, tt.h, 80, 24)
+		if got := [4]int{x0, y0, x1, y1}; got != tt.place {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.place, got)
+		}
+	}
+}
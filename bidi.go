@@ -0,0 +1,362 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"golang.org/x/text/unicode/bidi"
+)
+
+// bidiMirrors maps the runes which are mirrored when displayed right to
+// left to their mirror image.
+var bidiMirrors = map[rune]rune{
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'<': '>', '>': '<',
+	'«': '»', '»': '«',
+	'‹': '›', '›': '‹',
+}
+
+// bidiClasses returns the bidi class of each cell of line, after resolving
+// the weak types (rules W1 to W7 of the Unicode Bidirectional Algorithm).
+// Explicit embeddings and isolates are not supported, their control runes
+// are ignored.
+func bidiClasses(line []cell, rtl bool) []bidi.Class {
+	sos := bidi.L
+	if rtl {
+		sos = bidi.R
+	}
+
+	classes := make([]bidi.Class, len(line))
+	for i, c := range line {
+		p, _ := bidi.LookupRune(c.chr)
+		classes[i] = p.Class()
+		if c.chr == 0 {
+			classes[i] = bidi.WS // NULL is displayed as a space
+		}
+		if classes[i] == bidi.Control {
+			classes[i] = bidi.BN
+		}
+	}
+
+	// W1: non spacing marks take the type of the previous character
+	// W2: european numbers after an arabic letter are arabic numbers
+	// W3: arabic letters are right to left
+	prev, strong := sos, sos
+	for i, cl := range classes {
+		if cl == bidi.NSM {
+			cl = prev
+		}
+		switch cl {
+		case bidi.L, bidi.R, bidi.AL:
+			strong = cl
+		case bidi.EN:
+			if strong == bidi.AL {
+				cl = bidi.AN
+			}
+		}
+		if cl == bidi.AL {
+			cl = bidi.R
+		}
+		classes[i] = cl
+		prev = cl
+	}
+
+	// W4: a single separator between two numbers of the same type joins them
+	for i := 1; i+1 < len(classes); i++ {
+		before, after := classes[i-1], classes[i+1]
+		switch {
+		case classes[i] == bidi.ES && before == bidi.EN && after == bidi.EN:
+			classes[i] = bidi.EN
+		case classes[i] == bidi.CS && before == after && (before == bidi.EN || before == bidi.AN):
+			classes[i] = before
+		}
+	}
+
+	// W5: terminators next to european numbers are european numbers
+	for i := 0; i < len(classes); i++ {
+		if classes[i] != bidi.ET {
+			continue
+		}
+		j := i
+		for j < len(classes) && classes[j] == bidi.ET {
+			j++
+		}
+		if (i > 0 && classes[i-1] == bidi.EN) || (j < len(classes) && classes[j] == bidi.EN) {
+			for k := i; k < j; k++ {
+				classes[k] = bidi.EN
+			}
+		}
+		i = j - 1
+	}
+
+	// W6: remaining separators and terminators are neutral
+	// W7: european numbers after a left to right character are left to right
+	strong = sos
+	for i, cl := range classes {
+		switch cl {
+		case bidi.ES, bidi.ET, bidi.CS:
+			classes[i] = bidi.ON
+		case bidi.L, bidi.R:
+			strong = cl
+		case bidi.EN:
+			if strong == bidi.L {
+				classes[i] = bidi.L
+			}
+		}
+	}
+	return classes
+}
+
+// bidiIsRTL reports whether the paragraph direction of line is right to
+// left, i.e. whether its first strong character is right to left.
+func bidiIsRTL(line []cell) bool {
+	for _, c := range line {
+		p, _ := bidi.LookupRune(c.chr)
+		switch p.Class() {
+		case bidi.L:
+			return false
+		case bidi.R, bidi.AL:
+			return true
+		}
+	}
+	return false
+}
+
+// bidiLevels returns the embedding level of each cell of line (rules N1,
+// N2, I1, I2 and L1). Even levels are left to right, odd levels are right
+// to left.
+func bidiLevels(line []cell, rtl bool) []int {
+	base := 0
+	if rtl {
+		base = 1
+	}
+	classes := bidiClasses(line, rtl)
+	levels := make([]int, len(line))
+
+	// strongDir returns the direction of a class as seen by the neutrals:
+	// numbers count as right to left
+	strongDir := func(cl bidi.Class) (bidi.Class, bool) {
+		switch cl {
+		case bidi.L:
+			return bidi.L, true
+		case bidi.R, bidi.EN, bidi.AN:
+			return bidi.R, true
+		}
+		return 0, false
+	}
+	sos := bidi.L
+	if rtl {
+		sos = bidi.R
+	}
+
+	// N1, N2: sequences of neutrals take the direction of the surrounding
+	// characters if they agree, or else the embedding direction
+	for i := 0; i < len(classes); i++ {
+		if _, ok := strongDir(classes[i]); ok {
+			continue
+		}
+		j := i
+		for j < len(classes) {
+			if _, ok := strongDir(classes[j]); ok {
+				break
+			}
+			j++
+		}
+		before, after := sos, sos
+		if i > 0 {
+			before, _ = strongDir(classes[i-1])
+		}
+		if j < len(classes) {
+			after, _ = strongDir(classes[j])
+		}
+		dir := sos
+		if before == after {
+			dir = before
+		}
+		for k := i; k < j; k++ {
+			classes[k] = dir
+		}
+		i = j - 1
+	}
+
+	// I1, I2: implicit levels
+	for i, cl := range classes {
+		levels[i] = base
+		switch {
+		case base%2 == 0 && cl == bidi.R:
+			levels[i]++
+		case base%2 == 0 && (cl == bidi.EN || cl == bidi.AN):
+			levels[i] += 2
+		case base%2 == 1 && (cl == bidi.L || cl == bidi.EN || cl == bidi.AN):
+			levels[i]++
+		}
+	}
+
+	// L1: trailing whitespace is displayed in the paragraph direction
+	for i := len(line) - 1; i >= 0; i-- {
+		p, _ := bidi.LookupRune(line[i].chr)
+		if cl := p.Class(); line[i].chr != 0 && cl != bidi.WS && cl != bidi.BN {
+			break
+		}
+		levels[i] = base
+	}
+	return levels
+}
+
+// bidiOrder returns the visual order of the cells of line: the i-th cell
+// displayed is line[order[i]] (rule L2).
+func bidiOrder(line []cell, rtl bool) []int {
+	levels := bidiLevels(line, rtl)
+	order := make([]int, len(line))
+	highest, lowestOdd := 0, -1
+	for i, l := range levels {
+		order[i] = i
+		if l > highest {
+			highest = l
+		}
+		if l%2 == 1 && (lowestOdd == -1 || l < lowestOdd) {
+			lowestOdd = l
+		}
+	}
+	if lowestOdd == -1 {
+		return order
+	}
+
+	// reverse the runs of cells at each level, from the highest level to
+	// the lowest odd one
+	visualLevels := append([]int(nil), levels...)
+	for level := highest; level >= lowestOdd; level-- {
+		for i := 0; i < len(order); i++ {
+			if visualLevels[i] < level {
+				continue
+			}
+			j := i
+			for j < len(order) && visualLevels[j] >= level {
+				j++
+			}
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				order[a], order[b] = order[b], order[a]
+				visualLevels[a], visualLevels[b] = visualLevels[b], visualLevels[a]
+			}
+			i = j
+		}
+	}
+	return order
+}
+
+// bidiVisualLine returns the cells of line in visual order. Mirrored
+// characters (brackets, ...) displayed right to left are replaced by their
+// mirror image.
+func bidiVisualLine(line []cell, rtl bool) []cell {
+	levels := bidiLevels(line, rtl)
+	order := bidiOrder(line, rtl)
+	visual := make([]cell, len(line))
+	for i, logical := range order {
+		c := line[logical]
+		if levels[logical]%2 == 1 {
+			if m, ok := bidiMirrors[c.chr]; ok {
+				c.chr = m
+			}
+		}
+		visual[i] = c
+	}
+	return visual
+}
+
+// bidiIndexToColumn returns the column where the cell at logical index x
+// of line is displayed. The end of the line is displayed after the last
+// cell on the side of the paragraph direction: the column 0 is reserved for
+// it in right to left paragraphs, which are drawn from the column 1.
+func bidiIndexToColumn(line []cell, rtl bool, x int) int {
+	if x >= len(line) {
+		if rtl {
+			return 0
+		}
+		return lineWidth(line) + x - len(line)
+	}
+	order := bidiOrder(line, rtl)
+	col := 0
+	if rtl {
+		col = 1
+	}
+	for _, logical := range order {
+		if logical == x {
+			break
+		}
+		col += line[logical].width()
+	}
+	return col
+}
+
+// bidiColumnToIndex returns the logical index of the cell displayed at
+// column col. It is the inverse of bidiIndexToColumn.
+func bidiColumnToIndex(line []cell, rtl bool, col int) int {
+	if rtl {
+		if col == 0 {
+			return len(line)
+		}
+		col--
+	}
+	order := bidiOrder(line, rtl)
+	width := 0
+	for _, logical := range order {
+		width += line[logical].width()
+		if col < width {
+			return logical
+		}
+	}
+	if rtl {
+		// the right of a right to left paragraph is its start
+		return 0
+	}
+	return len(line) + col - width
+}
+
+// moveCursorVisually moves the cursor dx cells to the right (or to the left
+// if dx is negative) as the cells are displayed, which differs from the
+// logical order in right to left text. Moving out of the line goes to the
+// previous or next line.
+func (v *View) moveCursorVisually(dx int) {
+	if v.cy < 0 || v.cy >= len(v.lines) || dx == 0 {
+		v.MoveCursor(dx, 0)
+		return
+	}
+
+	line := v.lines[v.cy]
+	rtl := bidiIsRTL(line)
+	order := bidiOrder(line, rtl)
+	n := len(line)
+
+	// visual position of the cursor, the end of the line is at -1 for
+	// right to left paragraphs and at n for left to right ones
+	pos := n
+	if rtl {
+		pos = -1
+	}
+	for i, logical := range order {
+		if logical == v.cx {
+			pos = i
+		}
+	}
+
+	target := pos + dx
+	switch {
+	case target >= 0 && target < n:
+		v.cx = order[target]
+		v.MoveCursor(0, 0)
+	case !rtl && target == n, rtl && target == -1:
+		v.cx = n
+		v.MoveCursor(0, 0)
+	case !rtl && target > n, rtl && target < -1:
+		// past the end of the line
+		v.cx = n
+		v.MoveCursor(1, 0)
+	default:
+		// past the start of the line
+		v.cx = 0
+		v.MoveCursor(-1, 0)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBidiVisualLine(t *testing.T) {
+	tests := []struct {
+		logical, visual string
+		rtl             bool
+	}{
+		{"hello world", "hello world", false},
+		{"abc אבג 123", "abc 123 גבא", false},
+		{"abc אבג def", "abc גבא def", false},
+		{"אבג abc", "abc גבא", true},
+		{"אבג (12) ד", "ד (12) גבא", true},
+		{"שלום, 2021!", "!2021 ,םולש", true},
+	}
+
+	for _, tt := range tests {
+		v := (&Gui{}).newView("v", 0, 0, 30, 5, OutputNormal)
+		fmt.Fprint(v, tt.logical)
+		line := v.lines[0]
+		if rtl := bidiIsRTL(line); rtl != tt.rtl {
+			t.Errorf("%q: expected rtl to be %v", tt.logical, tt.rtl)
+		}
+		if got := lineType(bidiVisualLine(line, tt.rtl)).String(); got != tt.visual {
+			t.Errorf("%q: expected %q, got %q", tt.logical, tt.visual, got)
+		}
+	}
+}
+
+func TestBidiCursor(t *testing.T) {
+	v := (&Gui{}).newView("v", 0, 0, 30, 5, OutputNormal)
+	v.Bidi = true
+	fmt.Fprint(v, "ab אבג")
+
+	// visual order is "ab גבא": the cell א is displayed at column 5
+	x, _, _ := v.linesPosOnScreen(3, 0)
+	if x != 5 {
+		t.Errorf("expected column 5, got %d", x)
+	}
+	if x, _ := v.bufferPosition(5, 0); x != 3 {
+		t.Errorf("expected cell 3, got %d", x)
+	}
+
+	// moving right from "b" enters the hebrew word by its visual left
+	v.SetCursor(1, 0)
+	v.moveCursorVisually(1)
+	if x, _ := v.Cursor(); x != 2 {
+		t.Errorf("expected cursor on the space, got %d", x)
+	}
+	v.moveCursorVisually(1)
+	if x, _ := v.Cursor(); x != 5 {
+		t.Errorf("expected cursor on ג, got %d", x)
+	}
+	v.moveCursorVisually(1)
+	if x, _ := v.Cursor(); x != 4 {
+		t.Errorf("expected cursor on ב, got %d", x)
+	}
+}
+
+func TestBidiCursorRTL(t *testing.T) {
+	v := (&Gui{}).newView("v", 0, 0, 30, 5, OutputNormal)
+	v.Bidi = true
+	fmt.Fprint(v, "אבג")
+
+	// the column 0 is reserved for the end of the line: "גבא" is drawn
+	// from the column 1, and the end of the line is on its left
+	if got := lineType(v.bidiDisplayLine(v.wrappedLines()[0], v.lines[0])).String(); got != " גבא" {
+		t.Errorf("expected %q, got %q", " גבא", got)
+	}
+	for _, tt := range []struct{ x, col int }{{0, 3}, {2, 1}, {3, 0}} {
+		if col, _, _ := v.linesPosOnScreen(tt.x, 0); col != tt.col {
+			t.Errorf("cell %d: expected column %d, got %d", tt.x, tt.col, col)
+		}
+		if x, _ := v.bufferPosition(tt.col, 0); x != tt.x {
+			t.Errorf("column %d: expected cell %d, got %d", tt.col, tt.x, x)
+		}
+	}
+
+	// the end of the line is reached by moving left from its last cell
+	v.SetCursor(2, 0)
+	v.moveCursorVisually(-1)
+	if x, _ := v.Cursor(); x != 3 {
+		t.Errorf("expected cursor at the end of the line, got %d", x)
+	}
+}
+
+func TestBidiWrapRTL(t *testing.T) {
+	v := (&Gui{}).newView("v", 0, 0, 5, 5, OutputNormal)
+	v.Bidi = true
+	v.Wrap = true
+	fmt.Fprint(v, "אב cd")
+
+	// the line is right to left and 3 columns wide, the column 0 being
+	// reserved: "cd" is drawn in a right to left paragraph too
+	lines := v.wrappedLines()
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 display lines, got %d", len(lines))
+	}
+	var got []string
+	for _, dl := range lines {
+		got = append(got, lineType(v.bidiDisplayLine(dl, dl.cells)).String())
+	}
+	if expected := []string{"  בא", " cd"}; !equalStrings(got, expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	for _, tt := range []struct{ x, col, row int }{{0, 3, 0}, {3, 1, 1}, {4, 2, 1}, {5, 0, 1}} {
+		if col, row, _ := v.linesPosOnScreen(tt.x, 0); col != tt.col || row != tt.row {
+			t.Errorf("cell %d: expected (%d, %d), got (%d, %d)", tt.x, tt.col, tt.row, col, row)
+		}
+	}
+}
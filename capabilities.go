@@ -0,0 +1,103 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gdamore/tcell/v2/terminfo"
+)
+
+// Capabilities describes what the terminal is able to do, as detected from
+// the environment and the terminfo database.
+type Capabilities struct {
+	// Colors is the number of colors the terminal can display. It is 0 for
+	// terminals without color support.
+	Colors int
+
+	// TrueColor is true if the terminal supports 24bit colors.
+	TrueColor bool
+
+	// NoColor is true if the user asked for no colors by setting NO_COLOR.
+	NoColor bool
+
+	// Mouse is true if the terminal supports mouse events.
+	Mouse bool
+
+	// BracketedPaste is true if the terminal supports bracketed paste mode.
+	BracketedPaste bool
+
+	// Italics is true if the terminal is able to display italic text.
+	Italics bool
+}
+
+// DetectCapabilities returns the capabilities of the terminal referenced by
+// the TERM environment variable. COLORTERM and NO_COLOR are honoured.
+func DetectCapabilities() Capabilities {
+	term := os.Getenv("TERM")
+	ti, err := terminfo.LookupTerminfo(term)
+	if err != nil {
+		ti = nil
+	}
+	return detectCapabilities(os.Getenv, ti)
+}
+
+// detectCapabilities computes the capabilities from the given environment
+// lookup function and terminfo entry. ti may be nil if the terminal is unknown.
+func detectCapabilities(getenv func(string) string, ti *terminfo.Terminfo) Capabilities {
+	var c Capabilities
+	term := strings.ToLower(getenv("TERM"))
+
+	if ti != nil {
+		c.Colors = ti.Colors
+		c.TrueColor = ti.TrueColor
+		c.Mouse = ti.Mouse != ""
+		c.BracketedPaste = ti.EnablePaste != "" || ti.PasteStart != ""
+		c.Italics = ti.Italic != ""
+	} else {
+		switch {
+		case term == "" || term == "dumb":
+		case strings.Contains(term, "256color"):
+			c.Colors = 256
+		default:
+			c.Colors = 8
+		}
+		c.Mouse = strings.HasPrefix(term, "xterm")
+	}
+
+	if strings.Contains(term, "truecolor") || strings.Contains(term, "direct") {
+		c.TrueColor = true
+	}
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		c.TrueColor = true
+	}
+	if getenv("TCELL_TRUECOLOR") == "disable" {
+		c.TrueColor = false
+	}
+	if c.TrueColor && c.Colors < 1<<24 {
+		c.Colors = 1 << 24
+	}
+
+	// See https://no-color.org: the variable only counts when it is not empty.
+	c.NoColor = getenv("NO_COLOR") != ""
+
+	return c
+}
+
+// OutputMode returns the best OutputMode for the capabilities.
+func (c Capabilities) OutputMode() OutputMode {
+	switch {
+	case c.NoColor:
+		return OutputMonochrome
+	case c.TrueColor:
+		return OutputTrue
+	case c.Colors >= 256:
+		return Output256
+	default:
+		return OutputNormal
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/gdamore/tcell/v2/terminfo"
+)
+
+func TestDetectCapabilities(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		ti   *terminfo.Terminfo
+		want OutputMode
+	}{
+		{"unknown", map[string]string{"TERM": "dumb"}, nil, OutputNormal},
+		{"256 from TERM", map[string]string{"TERM": "xterm-256color"}, nil, Output256},
+		{"256 from terminfo", map[string]string{"TERM": "foo"}, &terminfo.Terminfo{Colors: 256}, Output256},
+		{"COLORTERM", map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, nil, OutputTrue},
+		{"terminfo truecolor", map[string]string{"TERM": "foo"}, &terminfo.Terminfo{Colors: 256, TrueColor: true}, OutputTrue},
+		{"NO_COLOR", map[string]string{"TERM": "xterm-256color", "COLORTERM": "24bit", "NO_COLOR": "1"}, nil, OutputMonochrome},
+	}
+
+	for _, tt := range tests {
+		getenv := func(k string) string { return tt.env[k] }
+		c := detectCapabilities(getenv, tt.ti)
+		if got := c.OutputMode(); got != tt.want {
+			t.Errorf("%s: got output mode %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	c := detectCapabilities(func(string) string { return "" }, &terminfo.Terminfo{
+		Colors:      8,
+		Mouse:       "\x1b[M",
+		EnablePaste: "\x1b[?2004h",
+		Italic:      "\x1b[3m",
+	})
+	if !c.Mouse || !c.BracketedPaste || !c.Italics {
+		t.Errorf("expected mouse, bracketed paste and italics to be detected, got %+v", c)
+	}
+}
+
+func TestMonochromeStyle(t *testing.T) {
+	st := getTcellStyle(ColorRed|AttrBold, ColorBlue, OutputMonochrome)
+	if fg, bg, attrs := st.Decompose(); fg != tcell.ColorDefault || bg != tcell.ColorDefault || attrs != tcell.AttrBold {
+		t.Errorf("expected the colors to be dropped and the effects kept, got %v, %v, %v", fg, bg, attrs)
+	}
+}
+
+func TestMonochromeRendering(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.outputMode = OutputMonochrome
+	g.Highlight = true
+	g.SetManagerFunc(func(g *Gui) error {
+		if v, err := g.SetView("list", 0, 0, 20, 4, 0); err != nil {
+			if !errors.Is(err, ErrUnknownView) {
+				return err
+			}
+			v.Title = "list"
+			v.Highlight = true
+			v.SelFgColor = ColorGreen
+			fmt.Fprint(v, "one\ntwo\nthree")
+			if err := v.SetCursor(0, 1); err != nil {
+				return err
+			}
+			if _, err := g.SetCurrentView("list"); err != nil {
+				return err
+			}
+		}
+		if v, err := g.SetView("text", 0, 5, 20, 7, 0); err != nil {
+			if !errors.Is(err, ErrUnknownView) {
+				return err
+			}
+			v.FgColor = ColorRed
+			fmt.Fprint(v, "hello")
+			v.setSelection(&selection{x1: 1})
+		}
+		if v, err := g.SetView("custom", 22, 0, 40, 3, 0); err != nil {
+			if !errors.Is(err, ErrUnknownView) {
+				return err
+			}
+			fmt.Fprint(v, "a\nb")
+			if err := v.SetHighlight(1, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	cells := []struct {
+		name          string
+		x, y          int
+		reverse, bold bool
+	}{
+		{"line", 1, 1, false, false},
+		{"highlighted line", 1, 2, true, true},
+		{"selected cell", 2, 6, true, false},
+		{"unselected cell", 3, 6, false, false},
+		{"SetHighlight line", 23, 2, true, false},
+		{"focused title", 2, 0, true, true},
+		{"focused frame", 0, 0, false, true},
+		{"frame", 0, 5, false, false},
+	}
+	for _, c := range cells {
+		_, _, st, _ := screen.GetContent(c.x, c.y)
+		fg, bg, attrs := st.Decompose()
+		if fg != tcell.ColorDefault || bg != tcell.ColorDefault {
+			t.Errorf("%s: expected no colors, got %v, %v", c.name, fg, bg)
+		}
+		if reverse := attrs&tcell.AttrReverse != 0; reverse != c.reverse {
+			t.Errorf("%s: expected reverse video %t", c.name, c.reverse)
+		}
+		if bold := attrs&tcell.AttrBold != 0; bold != c.bold {
+			t.Errorf("%s: expected bold %t", c.name, c.bold)
+		}
+	}
+}
@@ -0,0 +1,364 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Completer returns the candidates completing the text of an editable view.
+type Completer interface {
+	// Complete returns the candidates for the word at the cursor (x, y) of
+	// buffer, x being a cell index as returned by View.Cursor. The word is
+	// the text between spaces around the cursor, as returned by View.Word.
+	// The candidates don't need to be filtered: they are ranked by how
+	// well they match the part of the word before the cursor.
+	Complete(buffer string, x, y int) []string
+}
+
+// The CompleterFunc type is an adapter to allow the use of ordinary functions
+// as Completers.
+type CompleterFunc func(buffer string, x, y int) []string
+
+// Complete calls f(buffer, x, y).
+func (f CompleterFunc) Complete(buffer string, x, y int) []string {
+	return f(buffer, x, y)
+}
+
+// CompletionEditor is an Editor completing the word at the cursor of a view
+// with the candidates of a Completer, shown in a popup anchored below the
+// word. Tab opens the popup, or completes the word if there is only one
+// candidate. While the popup is open, Tab and the down arrow select the
+// next candidate, Backtab and the up arrow the previous one, and the
+// selected candidate replaces the word. Esc restores the word and closes
+// the popup, the other keys close it and are sent to Editor.
+//
+// The candidates are matched against the part of the word before the
+// cursor with a fuzzy match: the candidates which contain its runes in
+// order are kept, ranked by the number of consecutive runes, the runes at
+// the start of the words and the length of the candidates.
+type CompletionEditor struct {
+	Completer Completer
+
+	// Editor edits the text, DefaultEditor is used if it is nil.
+	Editor Editor
+
+	// If Async is true, Complete is called in a goroutine, and the popup
+	// is shown with Gui.Update.
+	Async bool
+
+	// If Auto is true, the popup is opened as runes are typed.
+	Auto bool
+
+	// MaxItems is the maximum number of candidates shown in the popup.
+	MaxItems int
+
+	// SelFgColor and SelBgColor are the colors of the selected candidate.
+	SelFgColor, SelBgColor Attribute
+
+	// candidates are the ranked candidates shown in the popup, and
+	// selected the index of the selected one, -1 if there is none
+	candidates []string
+	selected   int
+	open       bool
+
+	// the word being completed is the cells from start to end of the line
+	// y, word is its text before the completion
+	y, start, end int
+	word          string
+
+	// seq is incremented when the candidates requested are obsolete
+	seq int
+}
+
+// NewCompletionEditor returns an editor completing with c. A view needs its
+// own editor.
+func NewCompletionEditor(c Completer) *CompletionEditor {
+	return &CompletionEditor{
+		Completer:  c,
+		MaxItems:   8,
+		SelFgColor: ColorDefault | AttrReverse,
+		SelBgColor: ColorDefault,
+		selected:   -1,
+	}
+}
+
+// Edit completes the word at the cursor, or sends the key to the editor.
+func (e *CompletionEditor) Edit(v *View, key Key, ch rune, mod Modifier) {
+	if e.open && mod == ModNone {
+		switch key {
+		case KeyTab, KeyArrowDown:
+			e.selectCandidate(v, e.selected+1)
+			return
+		case KeyBacktab, KeyArrowUp:
+			e.selectCandidate(v, e.selected-1)
+			return
+		case KeyEsc:
+			e.replaceWord(v, e.word)
+			e.close(v)
+			return
+		}
+	}
+	if !e.open && key == KeyTab && mod == ModNone {
+		e.complete(v, true)
+		return
+	}
+
+	e.close(v)
+	e.editor().Edit(v, key, ch, mod)
+
+	typed := ch != 0 || key == KeyBackspace || key == KeyBackspace2
+	if e.Auto && typed {
+		e.complete(v, false)
+	}
+}
+
+// Paste closes the popup, and sends the text to the editor.
+func (e *CompletionEditor) Paste(v *View, text string) {
+	e.close(v)
+	pasteText(e.editor(), v, text)
+}
+
+// submit closes the popup, and tells the editor that v is submitted.
+func (e *CompletionEditor) submit(v *View) {
+	e.close(v)
+	submitEditor(e.editor(), v)
+}
+
+// editor returns the editor editing the text.
+func (e *CompletionEditor) editor() Editor {
+	if e.Editor == nil {
+		return DefaultEditor
+	}
+	return e.Editor
+}
+
+// complete requests the candidates for the word at the cursor of v. If
+// explicit is true, the first candidate is selected, or inserted if it is
+// the only one.
+func (e *CompletionEditor) complete(v *View, explicit bool) {
+	x, y := v.Cursor()
+	var line []cell
+	if y < len(v.lines) {
+		line = v.lines[y]
+	}
+	if x > len(line) {
+		x = len(line)
+	}
+	start, end := wordBounds(line, x)
+	prefix := lineType(line[start:x]).String()
+	if !explicit && prefix == "" {
+		return
+	}
+
+	e.seq++
+	seq, buffer := e.seq, v.Buffer()
+	word := lineType(line[start:end]).String()
+	show := func(candidates []string) {
+		if seq != e.seq {
+			return
+		}
+		e.y, e.start, e.end, e.word = y, start, end, word
+		e.show(v, rankCandidates(prefix, candidates), explicit)
+	}
+
+	if !e.Async {
+		show(e.Completer.Complete(buffer, x, y))
+		return
+	}
+	completer := e.Completer
+	go func() {
+		candidates := completer.Complete(buffer, x, y)
+		v.gui.Update(func(g *Gui) error {
+			show(candidates)
+			return nil
+		})
+	}()
+}
+
+// show opens the popup with the candidates.
+func (e *CompletionEditor) show(v *View, candidates []string, explicit bool) {
+	if len(candidates) == 0 {
+		e.close(v)
+		return
+	}
+	if explicit && len(candidates) == 1 {
+		e.replaceWord(v, candidates[0])
+		e.close(v)
+		return
+	}
+
+	e.candidates, e.selected, e.open = candidates, -1, true
+	if explicit {
+		e.selectCandidate(v, 0)
+		return
+	}
+	e.drawPopup(v)
+}
+
+// selectCandidate selects the i-th candidate, the first one after the last
+// one, and replaces the word with it.
+func (e *CompletionEditor) selectCandidate(v *View, i int) {
+	n := len(e.candidates)
+	e.selected = (i%n + n) % n
+	e.replaceWord(v, e.candidates[e.selected])
+	e.drawPopup(v)
+}
+
+// replaceWord replaces the word being completed with text, and moves the
+// cursor after it. The other cursors are removed.
+func (e *CompletionEditor) replaceWord(v *View, text string) {
+	v.ClearCursors()
+	if e.y >= len(v.lines) || e.end > len(v.lines[e.y]) {
+		return
+	}
+	line := v.lines[e.y]
+	cells := textCells(text, v.FgColor, v.BgColor)
+	newLine := make([]cell, 0, len(line)-(e.end-e.start)+len(cells))
+	newLine = append(newLine, line[:e.start]...)
+	newLine = append(newLine, cells...)
+	v.lines[e.y] = append(newLine, line[e.end:]...)
+	v.tainted = true
+
+	e.end = e.start + len(cells)
+	v.cx, v.cy = e.end, e.y
+	v.MoveCursor(0, 0)
+}
+
+// popupName returns the name of the view of the popup of v.
+func (e *CompletionEditor) popupName(v *View) string {
+	return v.name + ".completion"
+}
+
+// drawPopup shows the candidates in the popup, below the word.
+func (e *CompletionEditor) drawPopup(v *View) {
+	g := v.gui
+	name := e.popupName(v)
+	pv, err := g.View(name)
+	if errors.Is(err, ErrUnknownView) {
+		// the popup is placed by its anchor
+		pv, err = g.SetView(name, 0, 0, 1, 1, 0)
+		if !errors.Is(err, ErrUnknownView) {
+			return
+		}
+		pv.ZIndex = v.ZIndex + 1
+	} else if err != nil {
+		return
+	}
+
+	width := 1
+	for _, c := range e.candidates {
+		if w := lineWidth(textCells(c, ColorDefault, ColorDefault)); w > width {
+			width = w
+		}
+	}
+	height := len(e.candidates)
+	if e.MaxItems > 0 && height > e.MaxItems {
+		height = e.MaxItems
+	}
+	// the popup is aligned with the start of the word
+	x, y, _ := v.screenPosition(e.start, e.y)
+	if err := g.SetAnchor(name, Anchor{X: x, Y: y, Width: width, Height: height}); err != nil {
+		return
+	}
+
+	pv.lines = pv.lines[:0]
+	for i, c := range e.candidates {
+		line := textCells(c, ColorDefault, ColorDefault)
+		if i == e.selected {
+			line = padCells(textCells(c, e.SelFgColor, e.SelBgColor), width, e.SelFgColor, e.SelBgColor)
+		}
+		pv.lines = append(pv.lines, line)
+	}
+	pv.tainted = true
+
+	// the selected candidate is scrolled into view
+	_, rows := pv.Size()
+	if e.selected >= 0 && e.selected < pv.oy {
+		pv.oy = e.selected
+	}
+	if rows > 0 && e.selected >= pv.oy+rows {
+		pv.oy = e.selected - rows + 1
+	}
+}
+
+// close closes the popup, and discards the candidates requested.
+func (e *CompletionEditor) close(v *View) {
+	e.seq++
+	if !e.open {
+		return
+	}
+	e.open, e.candidates, e.selected = false, nil, -1
+	// the popup may have been deleted with the other views
+	_ = v.gui.DeleteView(e.popupName(v))
+}
+
+// rankCandidates returns the candidates matching pattern, the best matches
+// first.
+func rankCandidates(pattern string, candidates []string) []string {
+	type ranked struct {
+		text  string
+		score int
+	}
+	var matches []ranked
+	for _, c := range candidates {
+		if score, ok := fuzzyScore(pattern, c); ok {
+			matches = append(matches, ranked{c, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return len(matches[i].text) < len(matches[j].text)
+	})
+
+	result := make([]string, len(matches))
+	for i, m := range matches {
+		result[i] = m.text
+	}
+	return result
+}
+
+// fuzzyScore returns the score of s matching pattern, ignoring case. s
+// matches if it contains the runes of pattern in order: the score counts
+// the runes, with bonuses for the consecutive runes, the runes at the start
+// of words and a prefix.
+func fuzzyScore(pattern, s string) (score int, ok bool) {
+	p := []rune(strings.ToLower(pattern))
+	r := []rune(strings.ToLower(s))
+	if len(p) == 0 {
+		return 0, true
+	}
+
+	i, prev := 0, -2
+	for j, c := range r {
+		if i == len(p) {
+			break
+		}
+		if c != p[i] {
+			continue
+		}
+		score++
+		if j == prev+1 {
+			score += 5
+		}
+		if j == 0 || !unicode.IsLetter(r[j-1]) && !unicode.IsDigit(r[j-1]) {
+			score += 3
+		}
+		prev = j
+		i++
+	}
+	if i < len(p) {
+		return 0, false
+	}
+	if strings.HasPrefix(string(r), string(p)) {
+		score += 10
+	}
+	return score, true
+}
@@ -0,0 +1,123 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRankCandidates(t *testing.T) {
+	candidates := []string{"git-commit", "status", "checkout", "cherry-pick", "commit", "config"}
+
+	// prefixes first, then consecutive runes and starts of words
+	want := []string{"commit", "config", "git-commit", "checkout"}
+	if got := rankCandidates("co", candidates); !equalStrings(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	// shorter candidates first
+	want = []string{"checkout", "cherry-pick"}
+	if got := rankCandidates("CHK", candidates); !equalStrings(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestCompletionEditor(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewCompletionEditor(CompleterFunc(func(buffer string, x, y int) []string {
+		return []string{"status", "stash", "show"}
+	}))
+	g.SetManagerFunc(func(g *Gui) error {
+		v, err := g.SetView("input", 0, 0, 30, 2, 0)
+		if !errors.Is(err, ErrUnknownView) {
+			return err
+		}
+		v.Editable = true
+		v.SingleLine = true
+		v.Editor = e
+		_, err = g.SetCurrentView("input")
+		return err
+	})
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	testingScreen.SendStringAsKeys("git sta")
+	testingScreen.SendKeySync(KeyTab)
+	testingScreen.SendKeySync(KeyTab)
+	testingScreen.WaitSync()
+
+	v, err := g.View("input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v.Buffer(); got != "git status" {
+		t.Errorf("expected the second candidate to replace the word, got %q", got)
+	}
+	// the popup is below the word, the shortest candidate first
+	for x, r := range []rune("│status│") {
+		if got, _ := g.Rune(x+4, 4); got != r {
+			t.Errorf("column %d: expected %q, got %q", x+4, r, got)
+		}
+	}
+
+	testingScreen.SendKeySync(KeyEsc)
+	testingScreen.WaitSync()
+	if got := v.Buffer(); got != "git sta" {
+		t.Errorf("expected the word to be restored, got %q", got)
+	}
+	if _, err := g.View("input.completion"); !errors.Is(err, ErrUnknownView) {
+		t.Error("expected the popup to be closed")
+	}
+}
+
+func TestCompletionEditorSubmit(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewCompletionEditor(CompleterFunc(func(buffer string, x, y int) []string {
+		return []string{"status", "stash"}
+	}))
+	var submitted string
+	g.SetManagerFunc(func(g *Gui) error {
+		v, err := g.SetView("input", 0, 0, 30, 2, 0)
+		if !errors.Is(err, ErrUnknownView) {
+			return err
+		}
+		v.Editable = true
+		v.SingleLine = true
+		// the popup is closed with a history editor around the completion
+		v.Editor = &HistoryEditor{History: NewHistory(10), Editor: e}
+		v.OnSubmit = func(g *Gui, v *View) error {
+			submitted = v.Buffer()
+			return nil
+		}
+		_, err = g.SetCurrentView("input")
+		return err
+	})
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	testingScreen.SendStringAsKeys("sta")
+	testingScreen.SendKeySync(KeyTab)
+	testingScreen.WaitSync()
+	if _, err := g.View("input.completion"); err != nil {
+		t.Fatalf("expected the popup to be open: %v", err)
+	}
+
+	testingScreen.SendKeySync(KeyEnter)
+	testingScreen.WaitSync()
+	if submitted != "stash" {
+		t.Errorf("expected %q to be submitted, got %q", "stash", submitted)
+	}
+	if _, err := g.View("input.completion"); !errors.Is(err, ErrUnknownView) {
+		t.Error("expected the popup to be closed")
+	}
+}
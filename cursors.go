@@ -0,0 +1,250 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import "sort"
+
+// Point is a position in the buffer of a view: the cell X of the line Y.
+type Point struct {
+	X, Y int
+}
+
+// Cursors returns the positions of the cursors of the view, the main one,
+// returned by Cursor, first. EditWrite, EditDelete, EditNewLine, EditPaste
+// and EditDeleteToStartOfLine edit the text at every cursor, and the other
+// Edit functions move every cursor. The editors apply their other commands
+// to the main cursor, after removing the other ones.
+func (v *View) Cursors() []Point {
+	points := make([]Point, 0, len(v.cursors)+1)
+	points = append(points, Point{v.cx, v.cy})
+	return append(points, v.cursors...)
+}
+
+// AddCursor adds a cursor at the given point. As with SetCursor, a point
+// outside of the buffer is moved to the nearest buffer location. Adding a
+// cursor where there is already one does nothing.
+//
+// Rules:
+//
+//	y >= 0
+//	x >= 0
+func (v *View) AddCursor(x, y int) error {
+	if x < 0 || y < 0 {
+		return ErrInvalidPoint
+	}
+	x, y = v.clampPoint(x, y)
+	if v.cursorAt(x, y) || x == v.cx && y == v.cy {
+		return nil
+	}
+	v.cursors = append(v.cursors, Point{x, y})
+	v.tainted = true
+	return nil
+}
+
+// AddCursorBelow adds a cursor on the line below the lowest cursor, at the
+// same cell or at the end of the line if it is shorter.
+func (v *View) AddCursorBelow() {
+	last := Point{v.cx, v.cy}
+	for _, p := range v.cursors {
+		if p.Y > last.Y {
+			last = p
+		}
+	}
+	if last.Y+1 < len(v.lines) {
+		_ = v.AddCursor(last.X, last.Y+1)
+	}
+}
+
+// AddCursorAbove adds a cursor on the line above the highest cursor, at the
+// same cell or at the end of the line if it is shorter.
+func (v *View) AddCursorAbove() {
+	first := Point{v.cx, v.cy}
+	for _, p := range v.cursors {
+		if p.Y < first.Y {
+			first = p
+		}
+	}
+	if first.Y > 0 {
+		_ = v.AddCursor(first.X, first.Y-1)
+	}
+}
+
+// AddCursorsAtMatches adds a cursor at the start of every match of s in the
+// buffer, and returns the number of matches. The matches don't overlap, and
+// don't span several lines.
+func (v *View) AddCursorsAtMatches(s string) int {
+	pattern := textCells(s, ColorDefault, ColorDefault)
+	if len(pattern) == 0 {
+		return 0
+	}
+	matches := 0
+	for y, line := range v.lines {
+		for x := 0; x+len(pattern) <= len(line); x++ {
+			if !matchCells(line[x:x+len(pattern)], pattern) {
+				continue
+			}
+			_ = v.AddCursor(x, y)
+			matches++
+			x += len(pattern) - 1
+		}
+	}
+	return matches
+}
+
+// ClearCursors removes the cursors of the view but the main one.
+func (v *View) ClearCursors() {
+	if len(v.cursors) == 0 {
+		return
+	}
+	v.cursors = nil
+	v.tainted = true
+}
+
+// matchCells reports whether the characters of the cells of line are the
+// ones of pattern.
+func matchCells(line, pattern []cell) bool {
+	for i, c := range pattern {
+		if line[i].chr != c.chr || string(line[i].combining) != string(c.combining) {
+			return false
+		}
+	}
+	return true
+}
+
+// cursorAt reports whether one of the cursors other than the main one is at
+// the cell x of the line y.
+func (v *View) cursorAt(x, y int) bool {
+	for _, p := range v.cursors {
+		if p.X == x && p.Y == y {
+			return true
+		}
+	}
+	return false
+}
+
+// clampPoint returns the buffer location nearest to the point x, y.
+func (v *View) clampPoint(x, y int) (int, int) {
+	if len(v.lines) == 0 {
+		return 0, 0
+	}
+	y = clampInt(y, 0, len(v.lines)-1)
+	return clampInt(x, 0, len(v.lines[y])), y
+}
+
+// bufferLength returns the number of cells of the buffer, counting one for
+// each newline.
+func (v *View) bufferLength() int {
+	n := 0
+	for _, line := range v.lines {
+		n += len(line) + 1
+	}
+	if n > 0 {
+		n-- // no newline after the last line
+	}
+	return n
+}
+
+// pointOffset returns the offset in the buffer of the point x, y, counted as
+// in bufferLength.
+func (v *View) pointOffset(x, y int) int {
+	x, y = v.clampPoint(x, y)
+	offset := x
+	for _, line := range v.lines[:y] {
+		offset += len(line) + 1
+	}
+	return offset
+}
+
+// offsetPoint returns the point at an offset of the buffer.
+func (v *View) offsetPoint(offset int) (x, y int) {
+	for y, line := range v.lines {
+		if offset <= len(line) {
+			return clampInt(offset, 0, len(line)), y
+		}
+		offset -= len(line) + 1
+	}
+	if len(v.lines) == 0 {
+		return 0, 0
+	}
+	return len(v.lines[len(v.lines)-1]), len(v.lines) - 1
+}
+
+// forEachCursor runs edit at every cursor, from the first one in the buffer
+// to the last one. The cursors after the one edited are moved by the number
+// of cells added or removed by edit.
+func (v *View) forEachCursor(edit func()) {
+	if len(v.cursors) == 0 {
+		edit()
+		return
+	}
+
+	points := v.Cursors()
+	offsets := make([]int, len(points))
+	order := make([]int, len(points))
+	for i, p := range points {
+		offsets[i] = v.pointOffset(p.X, p.Y)
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return offsets[order[i]] < offsets[order[j]]
+	})
+
+	ox, oy := v.ox, v.oy
+	for n, i := range order {
+		v.cx, v.cy = v.offsetPoint(offsets[i])
+		length := v.bufferLength()
+		edit()
+		offsets[i] = v.pointOffset(v.cx, v.cy)
+		for _, j := range order[n+1:] {
+			offsets[j] += v.bufferLength() - length
+		}
+	}
+
+	v.cx, v.cy = v.offsetPoint(offsets[0])
+	v.cursors = nil
+	for _, offset := range offsets[1:] {
+		x, y := v.offsetPoint(offset)
+		_ = v.AddCursor(x, y)
+	}
+	v.tainted = true
+	// only the main cursor is scrolled into view
+	v.ox, v.oy = ox, oy
+	v.MoveCursor(0, 0)
+}
+
+// moveCursors moves all the cursors as MoveCursor moves the main one.
+func (v *View) moveCursors(dx, dy int) {
+	v.moveEachCursor(func() {
+		v.MoveCursor(dx, dy)
+	})
+}
+
+// moveEachCursor runs move, which moves the main cursor without editing the
+// text, for every cursor.
+func (v *View) moveEachCursor(move func()) {
+	if len(v.cursors) > 0 {
+		cx, cy, ox, oy := v.cx, v.cy, v.ox, v.oy
+		cursors := v.cursors
+		v.cursors = nil
+		for _, p := range cursors {
+			v.cx, v.cy = p.X, p.Y
+			move()
+			v.cursors = append(v.cursors, Point{v.cx, v.cy})
+		}
+		v.cx, v.cy, v.ox, v.oy = cx, cy, ox, oy
+		v.tainted = true
+	}
+	move()
+	v.dedupCursors()
+}
+
+// dedupCursors removes the cursors at the position of another one.
+func (v *View) dedupCursors() {
+	cursors := v.cursors
+	v.cursors = nil
+	for _, p := range cursors {
+		_ = v.AddCursor(p.X, p.Y)
+	}
+}
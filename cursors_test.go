@@ -0,0 +1,208 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"strings"
+	"testing"
+)
+
+// cursorsView returns a view with text, and its main cursor at x, y.
+func cursorsView(text string, x, y int) *View {
+	v := (&Gui{}).newView("v", 0, 0, 40, 10, OutputNormal)
+	v.lines = nil
+	for _, line := range strings.Split(text, "\n") {
+		v.lines = append(v.lines, textCells(line, ColorDefault, ColorDefault))
+	}
+	v.cx, v.cy = x, y
+	return v
+}
+
+func equalPoints(a, b []Point) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMultipleCursors(t *testing.T) {
+	tests := []struct {
+		text    string
+		cursors []Point
+		edit    func(v *View)
+		want    string
+		wantAt  []Point
+	}{
+		{
+			"foo\nbar\nbaz", []Point{{1, 0}, {1, 1}, {3, 2}},
+			func(v *View) { v.EditWrite('x') },
+			"fxoo\nbxar\nbazx", []Point{{2, 0}, {2, 1}, {4, 2}},
+		},
+		{
+			// the cursors after the main one on its line are moved
+			"foo bar", []Point{{0, 0}, {4, 0}},
+			func(v *View) { v.EditWrite('-') },
+			"-foo -bar", []Point{{1, 0}, {6, 0}},
+		},
+		{
+			"foo bar", []Point{{7, 0}, {3, 0}},
+			func(v *View) { v.EditDelete(true) },
+			"fo ba", []Point{{5, 0}, {2, 0}},
+		},
+		{
+			"ab\ncd\nef", []Point{{0, 1}, {0, 2}},
+			func(v *View) { v.EditDelete(true) },
+			"abcdef", []Point{{2, 0}, {4, 0}},
+		},
+		{
+			"ab\ncd", []Point{{2, 0}, {2, 1}},
+			func(v *View) { v.EditDelete(false) },
+			"abcd", []Point{{2, 0}, {4, 0}},
+		},
+		{
+			"foo bar", []Point{{1, 0}, {5, 0}},
+			func(v *View) { v.EditNewLine() },
+			"f\noo b\nar", []Point{{0, 1}, {0, 2}},
+		},
+		{
+			// the cursors meeting at the start of the line are merged
+			"abc", []Point{{1, 0}, {2, 0}},
+			func(v *View) { v.EditDelete(true); v.EditDelete(true) },
+			"c", []Point{{0, 0}},
+		},
+		{
+			"ab\ncd", []Point{{1, 0}, {1, 1}},
+			func(v *View) { v.EditPaste("x\ny") },
+			"ax\nyb\ncx\nyd", []Point{{1, 1}, {1, 3}},
+		},
+		{
+			"ab\ncd", []Point{{2, 0}, {1, 1}},
+			func(v *View) { v.EditDeleteToStartOfLine() },
+			"\nd", []Point{{0, 0}, {0, 1}},
+		},
+		{
+			"ab\ncd", []Point{{0, 0}, {1, 1}},
+			func(v *View) { v.EditGotoToEndOfLine() },
+			"ab\ncd", []Point{{2, 0}, {2, 1}},
+		},
+	}
+
+	for _, test := range tests {
+		v := cursorsView(test.text, test.cursors[0].X, test.cursors[0].Y)
+		for _, p := range test.cursors[1:] {
+			if err := v.AddCursor(p.X, p.Y); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		test.edit(v)
+		if got := v.Buffer(); got != test.want {
+			t.Errorf("%q: expected %q, got %q", test.text, test.want, got)
+		}
+		if got := v.Cursors(); !equalPoints(got, test.wantAt) {
+			t.Errorf("%q: expected the cursors %v, got %v", test.text, test.wantAt, got)
+		}
+	}
+}
+
+func TestAddCursors(t *testing.T) {
+	v := cursorsView("a long line\nab\nanother line", 8, 0)
+
+	v.AddCursorBelow()
+	v.AddCursorBelow()
+	v.AddCursorBelow()
+	want := []Point{{8, 0}, {2, 1}, {2, 2}}
+	if got := v.Cursors(); !equalPoints(got, want) {
+		t.Errorf("expected the cursors %v, got %v", want, got)
+	}
+	v.ClearCursors()
+
+	if n := v.AddCursorsAtMatches("line"); n != 2 {
+		t.Errorf("expected 2 matches, got %d", n)
+	}
+	want = []Point{{8, 0}, {7, 0}, {8, 2}}
+	if got := v.Cursors(); !equalPoints(got, want) {
+		t.Errorf("expected the cursors %v, got %v", want, got)
+	}
+
+	// the cursors move together, and are merged when they meet
+	v.moveCursors(0, -1)
+	want = []Point{{8, 0}, {7, 0}, {2, 1}}
+	if got := v.Cursors(); !equalPoints(got, want) {
+		t.Errorf("expected the cursors %v, got %v", want, got)
+	}
+	v.moveCursors(0, -1)
+	want = []Point{{8, 0}, {7, 0}, {2, 0}}
+	if got := v.Cursors(); !equalPoints(got, want) {
+		t.Errorf("expected the cursors %v, got %v", want, got)
+	}
+
+	simpleEditor(v, KeyEsc, 0, ModNone)
+	if got := v.Cursors(); len(got) != 1 {
+		t.Errorf("expected Esc to remove the cursors, got %v", got)
+	}
+}
+
+func TestCursorsEditors(t *testing.T) {
+	v := cursorsView("foo bar\nbaz qux", 7, 0)
+	_ = v.AddCursor(7, 1)
+	e := NewReadlineEditor()
+
+	// the moves and Ctrl+D apply to every cursor
+	e.Edit(v, KeyCtrlA, 0, ModNone)
+	e.Edit(v, KeyCtrlD, 0, ModNone)
+	e.Edit(v, KeyCtrlE, 0, ModNone)
+	if got := v.Buffer(); got != "oo bar\naz qux" {
+		t.Errorf("expected %q, got %q", "oo bar\naz qux", got)
+	}
+	want := []Point{{6, 0}, {6, 1}}
+	if got := v.Cursors(); !equalPoints(got, want) {
+		t.Errorf("expected the cursors %v, got %v", want, got)
+	}
+
+	// a kill only applies to the main cursor
+	e.Edit(v, KeyCtrlW, 0, ModNone)
+	if got := v.Buffer(); got != "oo \naz qux" {
+		t.Errorf("expected %q, got %q", "oo \naz qux", got)
+	}
+	want = []Point{{3, 0}}
+	if got := v.Cursors(); !equalPoints(got, want) {
+		t.Errorf("expected the cursors %v, got %v", want, got)
+	}
+
+	// the other cursors are used in the insert mode of vim
+	v = cursorsView("a\nb", 0, 0)
+	_ = v.AddCursor(0, 1)
+	vim := NewVimEditor()
+	vimType(vim, v, "ix")
+	if got := v.Buffer(); got != "xa\nxb" {
+		t.Errorf("expected %q, got %q", "xa\nxb", got)
+	}
+	vimType(vim, v, "\x1b")
+	if got := v.Cursors(); len(got) != 1 {
+		t.Errorf("expected Esc to remove the cursors, got %v", got)
+	}
+}
+
+func TestCursorsDrawing(t *testing.T) {
+	v := cursorsView("ab", 0, 0)
+	_ = v.AddCursor(1, 0)
+	_ = v.AddCursor(2, 0)
+
+	line := v.selectCells(v.wrappedLines()[0])
+	if len(line) != 3 || line[2].chr != ' ' {
+		t.Fatalf("expected a space for the cursor at the end of the line, got %v", line)
+	}
+	for x, c := range line {
+		if reverse := c.fgColor&AttrReverse != 0; reverse != (x > 0) {
+			t.Errorf("cell %d: expected reverse video %t", x, x > 0)
+		}
+	}
+}
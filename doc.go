@@ -91,22 +91,36 @@ and customized creating a new Editor and assigning it to *View.Editor:
 		Edit(v *View, key Key, ch rune, mod Modifier)
 	}
 
-DefaultEditor can be taken as example to create your own custom Editor:
+A custom Editor can be written as a function, handling some keys and
+sending the others to DefaultEditor:
 
-	var DefaultEditor Editor = EditorFunc(simpleEditor)
+	v.Editor = gocui.EditorFunc(upperEditor)
 
-	func simpleEditor(v *View, key Key, ch rune, mod Modifier) {
+	func upperEditor(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
 		switch {
-		case ch != 0 && mod == 0:
-			v.EditWrite(ch)
-		case key == KeySpace:
-			v.EditWrite(' ')
-		case key == KeyBackspace || key == KeyBackspace2:
-			v.EditDelete(true)
-		// ...
+		case ch != 0 && mod == gocui.ModNone:
+			v.EditWrite(unicode.ToUpper(ch))
+		case key == gocui.KeyCtrlL:
+			v.Clear()
+		default:
+			gocui.DefaultEditor.Edit(v, key, ch, mod)
 		}
 	}
 
+Bracketed paste is enabled unless Gui.SupportBracketedPaste is false: the
+text pasted in an editable view is sent to its editor at once if it is
+DefaultEditor or implements PasteEditor, without triggering the
+keybindings, and as typed keys otherwise. The text pasted while the current
+view is not editable is passed to Gui.OnPaste. A PasteEditor can forward
+the pasted text to DefaultPasteEditor.
+
+An editable view can have several cursors: View.AddCursor,
+View.AddCursorBelow, View.AddCursorAbove and View.AddCursorsAtMatches add
+cursors, and the text written or deleted with View.EditWrite,
+View.EditDelete, View.EditNewLine and View.EditPaste is edited at every
+cursor. With the default editor, Alt+Up, Alt+Down and Alt+click add cursors,
+and Esc or a click removes them.
+
 Colored text:
 
 Views allow to add colored text using ANSI colors. For example:
@@ -6,6 +6,8 @@ package gocui
 
 import (
 	"errors"
+	"reflect"
+	"strings"
 )
 
 // Editor interface must be satisfied by gocui editors.
@@ -23,16 +25,124 @@ func (f EditorFunc) Edit(v *View, key Key, ch rune, mod Modifier) {
 	f(v, key, ch, mod)
 }
 
-// DefaultEditor is the default editor.
+// PasteEditor is an Editor handling the text pasted in a view at once. The
+// text pasted in a view which editor is not a PasteEditor, nor DefaultEditor,
+// is sent to it as typed keys.
+type PasteEditor interface {
+	Editor
+	Paste(v *View, text string)
+}
+
+// DefaultEditor is the default editor. Single line views are edited with the
+// bindings of ReadlineEditor, each view having its own kill ring. The text
+// pasted in a view edited by DefaultEditor is inserted at once, as with
+// DefaultPasteEditor.
 var DefaultEditor Editor = EditorFunc(simpleEditor)
 
+// DefaultPasteEditor is DefaultEditor as a PasteEditor, for the editors
+// forwarding the pasted text to it.
+var DefaultPasteEditor PasteEditor = defaultPasteEditor{}
+
+// defaultPasteEditor is the type of DefaultPasteEditor.
+type defaultPasteEditor struct{}
+
+// Edit calls simpleEditor.
+func (defaultPasteEditor) Edit(v *View, key Key, ch rune, mod Modifier) {
+	simpleEditor(v, key, ch, mod)
+}
+
+// Paste inserts the text at the cursor.
+func (defaultPasteEditor) Paste(v *View, text string) {
+	v.EditPaste(text)
+}
+
+// isDefaultEditor reports whether editor is DefaultEditor or
+// DefaultPasteEditor. The functions can't be compared, their code pointers
+// are.
+func isDefaultEditor(editor Editor) bool {
+	switch e := editor.(type) {
+	case defaultPasteEditor:
+		return true
+	case EditorFunc:
+		return e != nil && reflect.ValueOf(e).Pointer() == reflect.ValueOf(simpleEditor).Pointer()
+	}
+	return false
+}
+
+// pasteText pastes text in v with editor, at once if it is a PasteEditor or
+// DefaultEditor, or as typed keys. In a single line view, the newlines are
+// typed as spaces.
+func pasteText(editor Editor, v *View, text string) {
+	if isDefaultEditor(editor) {
+		editor = DefaultPasteEditor
+	}
+	if e, ok := editor.(PasteEditor); ok {
+		e.Paste(v, text)
+		return
+	}
+	for _, ch := range normalizeNewlines(text) {
+		switch {
+		case ch == '\n' && !v.SingleLine:
+			editor.Edit(v, KeyEnter, 0, ModNone)
+		case ch == '\n', ch == ' ':
+			editor.Edit(v, KeySpace, 0, ModNone)
+		case ch == '\t':
+			editor.Edit(v, KeyTab, 0, ModNone)
+		default:
+			editor.Edit(v, 0, ch, ModNone)
+		}
+	}
+}
+
+// submitter is implemented by the editors with a state to reset when the
+// single line view they edit is submitted with Enter.
+type submitter interface {
+	submit(v *View)
+}
+
+// submitEditor tells editor that v is submitted, if it is a submitter.
+func submitEditor(editor Editor, v *View) {
+	if s, ok := editor.(submitter); ok {
+		s.submit(v)
+	}
+}
+
+// normalizeNewlines replaces the "\r\n" and "\r" newlines of text by "\n".
+func normalizeNewlines(text string) string {
+	return strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(text)
+}
+
 // simpleEditor is used as the default gocui editor.
 func simpleEditor(v *View, key Key, ch rune, mod Modifier) {
+	if v.SingleLine {
+		if v.readline == nil {
+			v.readline = NewReadlineEditor()
+		}
+		if v.readline.edit(v, key, ch, mod) {
+			return
+		}
+	}
+	editText(v, key, ch, mod)
+}
+
+// editText edits v with the keys of simpleEditor but the readline ones.
+func editText(v *View, key Key, ch rune, mod Modifier) {
 	if ch != 0 && mod == 0 {
 		v.EditWrite(ch)
 		return
 	}
 
+	if mod == ModAlt && !v.SingleLine {
+		switch key {
+		case KeyArrowDown:
+			v.AddCursorBelow()
+			return
+		case KeyArrowUp:
+			v.AddCursorAbove()
+			return
+		}
+	}
+
 	switch key {
 	case KeySpace:
 		v.EditWrite(' ')
@@ -43,75 +153,148 @@ func simpleEditor(v *View, key Key, ch rune, mod Modifier) {
 	case KeyInsert:
 		v.Overwrite = !v.Overwrite
 	case KeyEnter:
-		v.EditNewLine()
+		if !v.SingleLine {
+			v.EditNewLine()
+		}
 	case KeyArrowDown:
-		v.MoveCursor(0, 1)
+		if !v.SingleLine {
+			v.moveCursors(0, 1)
+		}
 	case KeyArrowUp:
-		v.MoveCursor(0, -1)
+		if !v.SingleLine {
+			v.moveCursors(0, -1)
+		}
 	case KeyArrowLeft:
-		v.MoveCursor(-1, 0)
+		if v.Bidi {
+			v.moveEachCursor(func() {
+				v.moveCursorVisually(-1)
+			})
+		} else {
+			v.moveCursors(-1, 0)
+		}
 	case KeyArrowRight:
-		v.MoveCursor(1, 0)
+		if v.Bidi {
+			v.moveEachCursor(func() {
+				v.moveCursorVisually(1)
+			})
+		} else {
+			v.moveCursors(1, 0)
+		}
 	case KeyTab:
 		v.EditWrite('\t')
 	case KeyEsc:
 		// If not here the esc key will act like the KeySpace
+		v.ClearCursors()
 	default:
 		v.EditWrite(ch)
 	}
 }
 
-// EditWrite writes a rune at the cursor position.
+// EditWrite writes a rune at the cursor position. A rune extending the
+// grapheme cluster before the cursor (e.g. a combining accent) is added to
+// it, and the cursor doesn't move. In a single line view, newlines are
+// written as spaces. The rune is written at every cursor of the view.
 func (v *View) EditWrite(ch rune) {
+	v.forEachCursor(func() {
+		v.editWrite(ch)
+	})
+}
+
+// editWrite writes a rune at the main cursor position.
+func (v *View) editWrite(ch rune) {
+	if v.SingleLine && (ch == '\n' || ch == '\r') {
+		ch = ' '
+	}
+	if v.cx > 0 && v.cy < len(v.lines) && v.cx <= len(v.lines[v.cy]) && extendsCluster(v.lines[v.cy][v.cx-1], ch) {
+		v.tainted = true
+		v.lines[v.cy][v.cx-1].addToCluster(ch)
+		return
+	}
 	v.writeRune(v.cx, v.cy, ch)
 	v.MoveCursor(1, 0)
 }
 
+// EditPaste inserts text at the cursor position at once, and moves the cursor
+// after it. The control characters other than newlines and tabs are
+// dropped. In a single line view, newlines are written as spaces. The text is
+// inserted at every cursor of the view.
+func (v *View) EditPaste(text string) {
+	text = strings.Map(func(r rune) rune {
+		if r < ' ' && r != '\n' && r != '\t' || r == 0x7f {
+			return -1
+		}
+		if r == '\n' && v.SingleLine {
+			return ' '
+		}
+		return r
+	}, normalizeNewlines(text))
+	if text == "" {
+		return
+	}
+	v.forEachCursor(func() {
+		v.cx, v.cy = v.insertText(v.cx, v.cy, text)
+		v.MoveCursor(0, 0)
+	})
+}
+
 // EditDeleteToStartOfLine is the equivalent of pressing ctrl+U in your terminal, it deletes to the start of the line. Or if you are already at the start of the line, it deletes the newline character
 func (v *View) EditDeleteToStartOfLine() {
-	x, _ := v.Cursor()
-	if x == 0 {
-		v.EditDelete(true)
-	} else {
-		// delete characters until we are the start of the line
-		for x > 0 {
-			v.EditDelete(true)
-			x, _ = v.Cursor()
+	v.forEachCursor(func() {
+		x, _ := v.Cursor()
+		if x == 0 {
+			v.editDelete(true)
+		} else {
+			// delete characters until we are the start of the line
+			for x > 0 {
+				v.editDelete(true)
+				x, _ = v.Cursor()
+			}
 		}
-	}
+	})
 }
 
 // EditGotoToStartOfLine takes you to the start of the current line
 func (v *View) EditGotoToStartOfLine() {
-	x, _ := v.Cursor()
-	for x > 0 {
-		v.MoveCursor(-1, 0)
-		x, _ = v.Cursor()
-	}
+	v.moveEachCursor(func() {
+		x, _ := v.Cursor()
+		for x > 0 {
+			v.MoveCursor(-1, 0)
+			x, _ = v.Cursor()
+		}
+	})
 }
 
 // EditGotoToEndOfLine takes you to the end of the line
 func (v *View) EditGotoToEndOfLine() {
-	_, y := v.Cursor()
-	_ = v.SetCursor(0, y+1)
-	x, newY := v.Cursor()
-	if newY == y {
-		// we must be on the last line, so lets move to the very end
-		prevX := -1
-		for prevX != x {
-			prevX = x
-			v.MoveCursor(1, 0)
-			x, _ = v.Cursor()
+	v.moveEachCursor(func() {
+		_, y := v.Cursor()
+		_ = v.SetCursor(0, y+1)
+		x, newY := v.Cursor()
+		if newY == y {
+			// we must be on the last line, so lets move to the very end
+			prevX := -1
+			for prevX != x {
+				prevX = x
+				v.MoveCursor(1, 0)
+				x, _ = v.Cursor()
+			}
+		} else {
+			// most left so now we're at the end of the original line
+			v.MoveCursor(-1, 0)
 		}
-	} else {
-		// most left so now we're at the end of the original line
-		v.MoveCursor(-1, 0)
-	}
+	})
 }
 
 // EditDelete deletes a rune at the cursor position. back determines the
-// direction.
+// direction. A rune is deleted at every cursor of the view.
 func (v *View) EditDelete(back bool) {
+	v.forEachCursor(func() {
+		v.editDelete(back)
+	})
+}
+
+// editDelete deletes a rune at the main cursor position.
+func (v *View) editDelete(back bool) {
 	x, y := v.cx, v.cy
 	if y < 0 {
 		return
@@ -145,8 +328,14 @@ func (v *View) EditDelete(back bool) {
 	v.deleteRune(v.cx, v.cy) // start/middle of the line
 }
 
-// EditNewLine inserts a new line under the cursor.
+// EditNewLine inserts a new line under the cursor, breaking the line at
+// every cursor of the view.
 func (v *View) EditNewLine() {
+	v.forEachCursor(v.editNewLine)
+}
+
+// editNewLine breaks the line at the main cursor position.
+func (v *View) editNewLine() {
 	v.breakLine(v.cx, v.cy)
 	v.ox = 0
 	v.cy = v.cy + 1
@@ -206,7 +395,7 @@ func (v *View) MoveCursor(dx, dy int) {
 		v.oy = newYOnScreen
 	}
 
-	if !v.Wrap {
+	if !v.wraps() {
 		if newXOnScreen > v.ox+maxX-1 {
 			v.ox = newXOnScreen - maxX + 1
 		}
@@ -268,6 +457,36 @@ func (v *View) writeRune(x, y int, ch rune) error {
 	return nil
 }
 
+// insertText inserts text in the view's internal buffer, at the position
+// corresponding to the point (x, y), and returns the position after it. The
+// newlines of text break the line.
+func (v *View) insertText(x, y int, text string) (endX, endY int) {
+	v.tainted = true
+	for len(v.lines) <= y {
+		v.lines = append(v.lines, nil)
+	}
+	line := v.lines[y]
+	if x > len(line) {
+		x = len(line)
+	}
+
+	parts := strings.Split(text, "\n")
+	lines := make([][]cell, len(parts))
+	for i, s := range parts {
+		lines[i] = textCells(s, v.FgColor, v.BgColor)
+	}
+	last := len(lines) - 1
+	endX, endY = len(lines[last]), y+last
+	if last == 0 {
+		endX += x
+	}
+
+	lines[last] = append(lines[last], line[x:]...)
+	lines[0] = append(append([]cell{}, line[:x]...), lines[0]...)
+	v.lines = append(v.lines[:y:y], append(lines, v.lines[y+1:]...)...)
+	return endX, endY
+}
+
 // deleteRune removes a rune from the view's internal buffer, at the
 // position corresponding to the point (x, y).
 // returns error if invalid point is specified.
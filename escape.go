@@ -134,7 +134,7 @@ func (ei *escapeInterpreter) parseOne(ch rune) (isEscape bool, err error) {
 		case ch == 'm':
 			var err error
 			switch ei.mode {
-			case OutputNormal:
+			case OutputNormal, OutputMonochrome:
 				err = ei.outputNormal()
 			case Output256:
 				err = ei.output256()
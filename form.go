@@ -0,0 +1,606 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FieldKind is the kind of a form field.
+type FieldKind int
+
+// Kinds of form fields.
+const (
+	// FieldInput is a single line text input.
+	FieldInput FieldKind = iota
+
+	// FieldPassword is a text input showing Mask instead of the text.
+	FieldPassword
+
+	// FieldNumber is a text input accepting only numbers.
+	FieldNumber
+
+	// FieldCheckbox is checked and unchecked with Space or a click.
+	FieldCheckbox
+
+	// FieldRadio shows all the options, one of them being selected with
+	// the arrow keys or a click.
+	FieldRadio
+
+	// FieldDropdown shows the selected option, and the others in a popup
+	// opened with Space or a click.
+	FieldDropdown
+)
+
+// Markers of the checkboxes, radio buttons and dropdowns.
+const (
+	checkboxChecked   = "[x] "
+	checkboxUnchecked = "[ ] "
+	radioSelected     = "(•) "
+	radioUnselected   = "( ) "
+	dropdownArrow     = '▾'
+)
+
+// FormField is a field of a Form.
+type FormField struct {
+	// Name identifies the field in the form.
+	Name string
+
+	// Label is the title of the view of the field, or the text of a
+	// checkbox.
+	Label string
+
+	// Kind is the kind of the field.
+	Kind FieldKind
+
+	// Value is the text of an input, password or number field. It is
+	// updated as the text is edited.
+	Value string
+
+	// Mask is drawn instead of the runes of a password field, '*' if it is
+	// 0.
+	Mask rune
+
+	// Checked is the state of a checkbox.
+	Checked bool
+
+	// Options are the options of a radio group or a dropdown, and Selected
+	// the index of the selected one.
+	Options  []string
+	Selected int
+
+	// Validate, if not nil, checks the value of the field, as returned by
+	// String. The error is shown on the frame of the field.
+	Validate func(value string) error
+
+	err error
+}
+
+// String returns the value of the field: the text of an input, "true" or
+// "false" for a checkbox, or the selected option.
+func (f *FormField) String() string {
+	switch f.Kind {
+	case FieldCheckbox:
+		return strconv.FormatBool(f.Checked)
+	case FieldRadio, FieldDropdown:
+		if f.Selected >= 0 && f.Selected < len(f.Options) {
+			return f.Options[f.Selected]
+		}
+		return ""
+	default:
+		return f.Value
+	}
+}
+
+// Number returns the value of a number field.
+func (f *FormField) Number() (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(f.Value), 64)
+}
+
+// Err returns the error of the field, as last validated.
+func (f *FormField) Err() error {
+	return f.err
+}
+
+// height returns the number of rows of the view of the field.
+func (f *FormField) height() int {
+	if f.Kind == FieldRadio {
+		return len(f.Options) + 2
+	}
+	return 3
+}
+
+// Form is a Manager showing fields in views stacked from the top of its
+// region, the view of each field being named after the form and the field,
+// like "form.field". Tab and Backtab move the focus to the next and the
+// previous fields, Enter submits the form and Esc cancels it.
+//
+// The fields are validated when they lose the focus and when the form is
+// submitted: the errors are shown on the bottom edge of their frame, until
+// they are fixed.
+type Form struct {
+	name           string
+	x0, y0, x1, y1 int
+	fields         []*FormField
+
+	// focus is the index of the field which has the focus
+	focus int
+
+	// popup is the dropdown whose popup is open, and popupCursor the row
+	// selected in the popup
+	popup       *FormField
+	popupCursor int
+
+	// ErrorFgColor is the color of the error messages.
+	ErrorFgColor Attribute
+
+	// SelFgColor and SelBgColor are the colors of the option selected in
+	// the popup of a dropdown.
+	SelFgColor, SelBgColor Attribute
+
+	// OnSubmit, if not nil, is called when the form is submitted and all
+	// its fields are valid.
+	OnSubmit func(g *Gui, f *Form) error
+
+	// OnCancel, if not nil, is called when the form is cancelled.
+	OnCancel func(g *Gui, f *Form) error
+}
+
+// NewForm returns a form with the given fields, covering the region from
+// (x0, y0) to (x1, y1).
+func NewForm(name string, x0, y0, x1, y1 int, fields ...*FormField) *Form {
+	return &Form{
+		name:         name,
+		x0:           x0,
+		y0:           y0,
+		x1:           x1,
+		y1:           y1,
+		fields:       fields,
+		ErrorFgColor: ColorRed,
+		SelFgColor:   ColorDefault | AttrReverse,
+		SelBgColor:   ColorDefault,
+	}
+}
+
+// SetDimensions moves the form to the region from (x0, y0) to (x1, y1).
+func (f *Form) SetDimensions(x0, y0, x1, y1 int) {
+	f.x0, f.y0, f.x1, f.y1 = x0, y0, x1, y1
+}
+
+// Fields returns the fields of the form.
+func (f *Form) Fields() []*FormField {
+	return f.fields
+}
+
+// Field returns the field with the given name, or nil.
+func (f *Form) Field(name string) *FormField {
+	for _, field := range f.fields {
+		if field.Name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// Values returns the values of the fields, by name.
+func (f *Form) Values() map[string]string {
+	values := make(map[string]string, len(f.fields))
+	for _, field := range f.fields {
+		values[field.Name] = field.String()
+	}
+	return values
+}
+
+// viewName returns the name of the view of a field.
+func (f *Form) viewName(field *FormField) string {
+	return f.name + "." + field.Name
+}
+
+// popupName returns the name of the view of the popup of the dropdowns.
+func (f *Form) popupName() string {
+	return f.name + ".popup"
+}
+
+// Focus gives the focus to the field which had it last, the first one
+// initially. The form must have been laid out.
+func (f *Form) Focus(g *Gui) error {
+	if len(f.fields) == 0 {
+		return nil
+	}
+	_, err := g.SetCurrentView(f.viewName(f.fields[f.focus]))
+	return err
+}
+
+// focusField gives the focus to the i-th field, and validates the field
+// which loses it.
+func (f *Form) focusField(g *Gui, i int) error {
+	if err := f.dismissPopup(g); err != nil {
+		return err
+	}
+	if i != f.focus {
+		f.validate(f.fields[f.focus])
+		f.focus = i
+	}
+	return f.Focus(g)
+}
+
+// NextField gives the focus to the next field, or the first one after the
+// last one.
+func (f *Form) NextField(g *Gui, v *View) error {
+	if len(f.fields) == 0 {
+		return nil
+	}
+	return f.focusField(g, (f.focus+1)%len(f.fields))
+}
+
+// PrevField gives the focus to the previous field, or the last one before
+// the first one.
+func (f *Form) PrevField(g *Gui, v *View) error {
+	if len(f.fields) == 0 {
+		return nil
+	}
+	return f.focusField(g, (f.focus+len(f.fields)-1)%len(f.fields))
+}
+
+// Submit validates the fields, and calls OnSubmit if they are all valid.
+// Otherwise the first invalid field gets the focus.
+func (f *Form) Submit(g *Gui, v *View) error {
+	invalid := -1
+	for i, field := range f.fields {
+		if f.validate(field) != nil && invalid < 0 {
+			invalid = i
+		}
+	}
+	if invalid >= 0 {
+		f.focus = invalid
+		return f.Focus(g)
+	}
+	if f.OnSubmit == nil {
+		return nil
+	}
+	return f.OnSubmit(g, f)
+}
+
+// Cancel calls OnCancel.
+func (f *Form) Cancel(g *Gui, v *View) error {
+	if f.OnCancel == nil {
+		return nil
+	}
+	return f.OnCancel(g, f)
+}
+
+// Validate validates the fields, and returns the first error.
+func (f *Form) Validate() error {
+	var first error
+	for _, field := range f.fields {
+		if err := f.validate(field); err != nil && first == nil {
+			first = fmt.Errorf("%s: %w", field.Name, err)
+		}
+	}
+	return first
+}
+
+// validate validates a field, and returns its error.
+func (f *Form) validate(field *FormField) error {
+	field.err = nil
+	if field.Kind == FieldNumber && strings.TrimSpace(field.Value) != "" {
+		if _, err := field.Number(); err != nil {
+			field.err = errors.New("invalid number")
+		}
+	}
+	if field.err == nil && field.Validate != nil {
+		field.err = field.Validate(field.String())
+	}
+	return field.err
+}
+
+// Layout creates the views of the fields and draws them.
+func (f *Form) Layout(g *Gui) error {
+	y := f.y0
+	for _, field := range f.fields {
+		v, err := g.SetView(f.viewName(field), f.x0, y, f.x1, y+field.height()-1, 0)
+		if err != nil {
+			if !errors.Is(err, ErrUnknownView) {
+				return err
+			}
+			if err := f.initField(g, v, field); err != nil {
+				return err
+			}
+		}
+		y += field.height()
+
+		f.drawField(v, field)
+	}
+	return f.layoutPopup(g)
+}
+
+// initField initializes the view of a field and sets its keybindings.
+func (f *Form) initField(g *Gui, v *View, field *FormField) error {
+	switch field.Kind {
+	case FieldInput, FieldPassword, FieldNumber:
+		v.Editable = true
+		v.SingleLine = true
+		v.Editor = EditorFunc(func(v *View, key Key, ch rune, mod Modifier) {
+			fieldEditor(field, v, key, ch, mod)
+		})
+		if field.Kind == FieldPassword {
+			v.Mask = field.Mask
+			if v.Mask == 0 {
+				v.Mask = '*'
+			}
+		}
+		fmt.Fprint(v, field.Value)
+		// SetCursor clamps the position to the end of the line
+		if err := v.SetCursor(len(field.Value), 0); err != nil {
+			return err
+		}
+	}
+
+	bindings := map[Key]func(*Gui, *View) error{
+		KeyTab:     f.NextField,
+		KeyBacktab: f.PrevField,
+		KeyEnter:   f.Submit,
+		KeyEsc:     f.Cancel,
+		MouseLeft:  f.onClick,
+	}
+	switch field.Kind {
+	case FieldCheckbox, FieldDropdown:
+		bindings[KeySpace] = f.onSpace
+	case FieldRadio:
+		bindings[KeyArrowUp] = f.onRadioUp
+		bindings[KeyArrowDown] = f.onRadioDown
+	}
+	for key, handler := range bindings {
+		if err := g.SetKeybinding(v.name, key, ModNone, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldEditor edits a text field, and updates its value. A number field
+// only accepts the runes of numbers.
+func fieldEditor(field *FormField, v *View, key Key, ch rune, mod Modifier) {
+	if field.Kind == FieldNumber && ch != 0 && mod == ModNone && !strings.ContainsRune("0123456789.-+eE", ch) {
+		return
+	}
+	simpleEditor(v, key, ch, mod)
+	field.Value = strings.TrimSuffix(v.Buffer(), "\n")
+}
+
+// drawField draws the content of a field which is not a text field, and
+// shows the error of the field.
+func (f *Form) drawField(v *View, field *FormField) {
+	switch field.Kind {
+	case FieldInput, FieldPassword, FieldNumber:
+		v.Title = field.Label
+	case FieldCheckbox:
+		text := checkboxUnchecked + field.Label
+		if field.Checked {
+			text = checkboxChecked + field.Label
+		}
+		v.lines = [][]cell{textCells(text, ColorDefault, ColorDefault)}
+	case FieldRadio:
+		v.Title = field.Label
+		v.lines = v.lines[:0]
+		for i, option := range field.Options {
+			text := radioUnselected + option
+			if i == field.Selected {
+				text = radioSelected + option
+			}
+			v.lines = append(v.lines, textCells(text, ColorDefault, ColorDefault))
+		}
+	case FieldDropdown:
+		v.Title = field.Label
+		width, _ := v.Size()
+		line := truncateCells(textCells(field.String(), ColorDefault, ColorDefault), width-2)
+		line = padCells(line, width-1, ColorDefault, ColorDefault)
+		v.lines = [][]cell{append(line, cell{chr: dropdownArrow, fgColor: ColorDefault, bgColor: ColorDefault})}
+	}
+	v.tainted = true
+
+	// an error is validated again as the field is edited, until it is
+	// fixed
+	v.Labels = nil
+	if field.err != nil && f.validate(field) != nil {
+		label := NewFrameLabel(EdgeBottom, AlignLeft, field.err.Error())
+		label.Spans[0].FgColor = f.ErrorFgColor
+		v.Labels = []FrameLabel{label}
+	}
+}
+
+// index returns the index of the field with the given view name, or -1.
+func (f *Form) index(name string) int {
+	for i, field := range f.fields {
+		if f.viewName(field) == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// onClick gives the focus to the clicked field, and checks it, selects the
+// clicked option or opens the popup of a dropdown.
+func (f *Form) onClick(g *Gui, v *View) error {
+	i := f.index(v.name)
+	if i < 0 {
+		return nil
+	}
+	if err := f.focusField(g, i); err != nil {
+		return err
+	}
+
+	field := f.fields[i]
+	switch field.Kind {
+	case FieldRadio:
+		if _, y := v.Cursor(); y < len(field.Options) {
+			field.Selected = y
+		}
+	case FieldCheckbox, FieldDropdown:
+		return f.onSpace(g, v)
+	}
+	return nil
+}
+
+// onSpace checks or unchecks a checkbox, or opens the popup of a dropdown.
+func (f *Form) onSpace(g *Gui, v *View) error {
+	i := f.index(v.name)
+	if i < 0 {
+		return nil
+	}
+	field := f.fields[i]
+	if field.Kind == FieldCheckbox {
+		field.Checked = !field.Checked
+		return nil
+	}
+	return f.openPopup(g, field)
+}
+
+// onRadioUp selects the previous option of a radio group.
+func (f *Form) onRadioUp(g *Gui, v *View) error {
+	if i := f.index(v.name); i >= 0 && f.fields[i].Selected > 0 {
+		f.fields[i].Selected--
+	}
+	return nil
+}
+
+// onRadioDown selects the next option of a radio group.
+func (f *Form) onRadioDown(g *Gui, v *View) error {
+	if i := f.index(v.name); i >= 0 && f.fields[i].Selected < len(f.fields[i].Options)-1 {
+		f.fields[i].Selected++
+	}
+	return nil
+}
+
+// openPopup opens the popup of a dropdown, anchored below the field.
+func (f *Form) openPopup(g *Gui, field *FormField) error {
+	if f.popup != nil || len(field.Options) == 0 {
+		return nil
+	}
+	fv, err := g.View(f.viewName(field))
+	if err != nil {
+		return err
+	}
+
+	name := f.popupName()
+	v, err := g.SetView(name, 0, 0, 1, 1, 0)
+	if err != nil && !errors.Is(err, ErrUnknownView) {
+		return err
+	}
+	bindings := []struct {
+		key     Key
+		handler func(*Gui, *View) error
+	}{
+		{KeyArrowUp, f.onPopupUp},
+		{KeyArrowDown, f.onPopupDown},
+		{KeyEnter, f.onPopupEnter},
+		{KeySpace, f.onPopupEnter},
+		{KeyEsc, f.onPopupEsc},
+		{MouseLeft, f.onPopupClick},
+	}
+	for _, b := range bindings {
+		if err := g.SetKeybinding(name, b.key, ModNone, b.handler); err != nil {
+			return err
+		}
+	}
+	v.ZIndex = fv.ZIndex + 1
+
+	f.popup, f.popupCursor = field, field.Selected
+	if err := g.SetAnchor(name, Anchor{View: fv.name, Placement: PlaceBelow, Width: fv.x1 - fv.x0 - 1, Height: len(field.Options)}); err != nil {
+		return err
+	}
+	f.drawPopup(v)
+	_, err = g.SetCurrentView(name)
+	return err
+}
+
+// closePopup closes the popup of the dropdown, and gives the focus back to
+// its field.
+func (f *Form) closePopup(g *Gui) error {
+	if err := f.dismissPopup(g); err != nil {
+		return err
+	}
+	return f.Focus(g)
+}
+
+// dismissPopup closes the popup of the dropdown, if it is open.
+func (f *Form) dismissPopup(g *Gui) error {
+	if f.popup == nil {
+		return nil
+	}
+	f.popup = nil
+	g.DeleteKeybindings(f.popupName())
+	return g.DeleteView(f.popupName())
+}
+
+// layoutPopup draws the popup of the dropdown, if it is open.
+func (f *Form) layoutPopup(g *Gui) error {
+	if f.popup == nil {
+		return nil
+	}
+	v, err := g.View(f.popupName())
+	if err != nil {
+		return err
+	}
+	f.drawPopup(v)
+	return nil
+}
+
+// drawPopup writes the options of the dropdown in v.
+func (f *Form) drawPopup(v *View) {
+	width, _ := v.Size()
+	v.lines = v.lines[:0]
+	for i, option := range f.popup.Options {
+		line := textCells(option, ColorDefault, ColorDefault)
+		if i == f.popupCursor {
+			line = padCells(textCells(option, f.SelFgColor, f.SelBgColor), width, f.SelFgColor, f.SelBgColor)
+		}
+		v.lines = append(v.lines, line)
+	}
+	v.tainted = true
+}
+
+// onPopupUp selects the previous option in the popup.
+func (f *Form) onPopupUp(g *Gui, v *View) error {
+	if f.popupCursor > 0 {
+		f.popupCursor--
+	}
+	return nil
+}
+
+// onPopupDown selects the next option in the popup.
+func (f *Form) onPopupDown(g *Gui, v *View) error {
+	if f.popup != nil && f.popupCursor < len(f.popup.Options)-1 {
+		f.popupCursor++
+	}
+	return nil
+}
+
+// onPopupEnter selects the option of the dropdown, and closes the popup.
+func (f *Form) onPopupEnter(g *Gui, v *View) error {
+	if f.popup != nil {
+		f.popup.Selected = f.popupCursor
+	}
+	return f.closePopup(g)
+}
+
+// onPopupEsc closes the popup, keeping the option of the dropdown.
+func (f *Form) onPopupEsc(g *Gui, v *View) error {
+	return f.closePopup(g)
+}
+
+// onPopupClick selects the clicked option. The cursor of the view was moved
+// to the click.
+func (f *Form) onPopupClick(g *Gui, v *View) error {
+	_, y := v.Cursor()
+	if f.popup == nil || y >= len(f.popup.Options) {
+		return nil
+	}
+	f.popupCursor = y
+	return f.onPopupEnter(g, v)
+}
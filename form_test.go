@@ -0,0 +1,109 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormValidate(t *testing.T) {
+	required := func(value string) error {
+		if value == "" {
+			return errors.New("required")
+		}
+		return nil
+	}
+	form := NewForm("form", 0, 0, 20, 20,
+		&FormField{Name: "name", Validate: required},
+		&FormField{Name: "age", Kind: FieldNumber, Value: "4x"},
+		&FormField{Name: "color", Kind: FieldRadio, Options: []string{"red", "blue"}, Selected: 1},
+	)
+
+	if err := form.Validate(); err == nil || err.Error() != "name: required" {
+		t.Errorf("expected the name to be required, got %v", err)
+	}
+	if err := form.Field("age").Err(); err == nil {
+		t.Error("expected the age to be invalid")
+	}
+
+	form.Field("name").Value = "gocui"
+	form.Field("age").Value = "4"
+	if err := form.Validate(); err != nil {
+		t.Errorf("expected the form to be valid, got %v", err)
+	}
+	want := map[string]string{"name": "gocui", "age": "4", "color": "blue"}
+	for name, value := range form.Values() {
+		if want[name] != value {
+			t.Errorf("%s: expected %q, got %q", name, want[name], value)
+		}
+	}
+}
+
+func TestFormKeys(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	form := NewForm("form", 0, 0, 20, 20,
+		&FormField{Name: "name", Label: "Name", Validate: func(value string) error {
+			if value == "" {
+				return errors.New("required")
+			}
+			return nil
+		}},
+		&FormField{Name: "ok", Label: "OK", Kind: FieldCheckbox},
+		&FormField{Name: "size", Label: "Size", Kind: FieldDropdown, Options: []string{"S", "M", "L"}},
+	)
+	submitted := false
+	form.OnSubmit = func(g *Gui, f *Form) error {
+		submitted = true
+		return nil
+	}
+	g.SetManager(form)
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	done := make(chan struct{})
+	g.Update(func(g *Gui) error {
+		defer close(done)
+		return form.Focus(g)
+	})
+	<-done
+	testingScreen.WaitSync()
+
+	// the empty name is shown as an error when the field is left
+	testingScreen.SendKeySync(KeyTab)
+	testingScreen.SendKeySync(KeySpace)
+	testingScreen.SendKeySync(KeyTab)
+	testingScreen.SendKeySync(KeySpace)
+	testingScreen.SendKeySync(KeyArrowDown)
+	testingScreen.SendKeySync(KeyEnter)
+	testingScreen.WaitSync()
+
+	want := []rune("└─required")
+	for x, r := range want {
+		if got, _ := g.Rune(x, 2); got != r {
+			t.Errorf("column %d: expected %q, got %q", x, r, got)
+		}
+	}
+	if v := form.Values(); v["ok"] != "true" || v["size"] != "M" {
+		t.Errorf("expected the checkbox to be checked and M to be selected, got %v", v)
+	}
+
+	// the form is not submitted until the name is set
+	testingScreen.SendKeySync(KeyEnter)
+	testingScreen.WaitSync()
+	if submitted || g.CurrentView().Name() != "form.name" {
+		t.Fatal("expected the focus to go back to the name")
+	}
+	testingScreen.SendStringAsKeys("x")
+	testingScreen.SendKeySync(KeyEnter)
+	testingScreen.WaitSync()
+	if !submitted {
+		t.Error("expected the form to be submitted")
+	}
+}
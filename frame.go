@@ -0,0 +1,289 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import "strings"
+
+// The frame presets, returned by the FrameRunes functions. They define the
+// 11 runes, so they work with Gui.SupportOverlaps.
+var (
+	frameRunesSingle  = [...]rune{'─', '│', '┌', '┐', '└', '┘', '├', '┤', '┬', '┴', '┼'}
+	frameRunesRounded = [...]rune{'─', '│', '╭', '╮', '╰', '╯', '├', '┤', '┬', '┴', '┼'}
+	frameRunesHeavy   = [...]rune{'━', '┃', '┏', '┓', '┗', '┛', '┣', '┫', '┳', '┻', '╋'}
+	frameRunesDouble  = [...]rune{'═', '║', '╔', '╗', '╚', '╝', '╠', '╣', '╦', '╩', '╬'}
+	frameRunesDashed  = [...]rune{'╌', '╎', '┌', '┐', '└', '┘', '├', '┤', '┬', '┴', '┼'}
+	frameRunesASCII   = [...]rune{'-', '|', '+', '+', '+', '+', '+', '+', '+', '+', '+'}
+)
+
+// The FrameRunes functions return a frame preset, which can be used as
+// View.FrameRunes or View.SelFrameRunes. Each call returns a new slice, so
+// modifying it doesn't change the preset.
+
+// FrameRunesSingle returns the default frame.
+func FrameRunesSingle() []rune {
+	return append([]rune(nil), frameRunesSingle[:]...)
+}
+
+// FrameRunesRounded returns a single frame with rounded corners.
+func FrameRunesRounded() []rune {
+	return append([]rune(nil), frameRunesRounded[:]...)
+}
+
+// FrameRunesHeavy returns a frame drawn with heavy lines.
+func FrameRunesHeavy() []rune {
+	return append([]rune(nil), frameRunesHeavy[:]...)
+}
+
+// FrameRunesDouble returns a frame drawn with double lines.
+func FrameRunesDouble() []rune {
+	return append([]rune(nil), frameRunesDouble[:]...)
+}
+
+// FrameRunesDashed returns a single frame with dashed edges.
+func FrameRunesDashed() []rune {
+	return append([]rune(nil), frameRunesDashed[:]...)
+}
+
+// FrameRunesASCII returns a frame which only uses ASCII characters. This
+// is the frame used when Gui.ASCII is true.
+func FrameRunesASCII() []rune {
+	return append([]rune(nil), frameRunesASCII[:]...)
+}
+
+// GetFrameRunes returns the frame preset with the given name: single,
+// rounded, heavy, double, dashed or ascii. It returns nil if the name is
+// unknown, which means the default frame when used as View.FrameRunes. Like
+// the FrameRunes functions, it returns a new slice.
+func GetFrameRunes(name string) []rune {
+	switch strings.ToLower(name) {
+	case "single":
+		return FrameRunesSingle()
+	case "rounded":
+		return FrameRunesRounded()
+	case "heavy":
+		return FrameRunesHeavy()
+	case "double":
+		return FrameRunesDouble()
+	case "dashed":
+		return FrameRunesDashed()
+	case "ascii":
+		return FrameRunesASCII()
+	}
+	return nil
+}
+
+// frameRunes returns the runes used to draw the frame of v. SelFrameRunes
+// are used for the current view when Highlight is true.
+func (g *Gui) frameRunes(v *View) []rune {
+	if g.Highlight && v == g.currentView && len(v.SelFrameRunes) > 0 {
+		return v.SelFrameRunes
+	}
+	return v.FrameRunes
+}
+
+// lineWeight is the kind of line used to draw one arm of a box drawing
+// rune.
+type lineWeight byte
+
+const (
+	lineNone lineWeight = iota
+	lineLight
+	lineHeavy
+	lineDouble
+)
+
+// frameWeight returns the kind of line of a frame. lineNone is returned for
+// custom runes which can't be combined with the box drawing runes.
+func frameWeight(runes []rune) lineWeight {
+	if len(runes) == 0 {
+		return lineLight
+	}
+	switch runes[0] {
+	case '─', '┄', '┈', '╌':
+		return lineLight
+	case '━', '┅', '┉', '╍':
+		return lineHeavy
+	case '═':
+		return lineDouble
+	}
+	return lineNone
+}
+
+// junctionRune returns the rune for the corner of v at (x, y) when overlaps
+// are supported. directions are the edges of v going out of the corner, the
+// other arms come from v.Overlaps. When the overlapping views use another
+// kind of line, the matching mixed box drawing rune is returned.
+func (g *Gui) junctionRune(v *View, runes []rune, x, y int, directions byte) rune {
+	fallback := corner(runes, v.Overlaps, directions)
+	own := frameWeight(runes)
+	if own == lineNone {
+		return fallback
+	}
+
+	var arms [4]lineWeight
+	mixed := false
+	for i, dir := range []byte{TOP, BOTTOM, LEFT, RIGHT} {
+		switch {
+		case directions&dir != 0:
+			arms[i] = own
+		case v.Overlaps&dir != 0:
+			w := g.neighbourWeight(v, x, y, dir)
+			if w == lineNone {
+				w = own
+			}
+			arms[i] = w
+			mixed = mixed || w != own
+		}
+	}
+	if !mixed {
+		return fallback
+	}
+	if r, ok := boxRunes[arms]; ok {
+		return r
+	}
+	return fallback
+}
+
+// neighbourWeight returns the kind of line of the topmost view, other than
+// v, having an edge going out of (x, y) in the given direction.
+func (g *Gui) neighbourWeight(v *View, x, y int, dir byte) lineWeight {
+	weight := lineNone
+	for _, w := range g.views {
+		if w == v || !w.Visible || !w.Frame {
+			continue
+		}
+		vertical := x == w.x0 || x == w.x1
+		horizontal := y == w.y0 || y == w.y1
+		var found bool
+		switch dir {
+		case TOP:
+			found = vertical && w.y0 < y && y <= w.y1
+		case BOTTOM:
+			found = vertical && w.y0 <= y && y < w.y1
+		case LEFT:
+			found = horizontal && w.x0 < x && x <= w.x1
+		case RIGHT:
+			found = horizontal && w.x0 <= x && x < w.x1
+		}
+		if found {
+			weight = frameWeight(g.frameRunes(w))
+		}
+	}
+	return weight
+}
+
+// boxRunes maps the arms (up, down, left, right) of a box drawing rune to
+// the rune.
+var boxRunes = map[[4]lineWeight]rune{
+	{lineNone, lineNone, lineLight, lineLight}:       '─',
+	{lineNone, lineNone, lineHeavy, lineHeavy}:       '━',
+	{lineLight, lineLight, lineNone, lineNone}:       '│',
+	{lineHeavy, lineHeavy, lineNone, lineNone}:       '┃',
+	{lineNone, lineLight, lineNone, lineLight}:       '┌',
+	{lineNone, lineLight, lineNone, lineHeavy}:       '┍',
+	{lineNone, lineHeavy, lineNone, lineLight}:       '┎',
+	{lineNone, lineHeavy, lineNone, lineHeavy}:       '┏',
+	{lineNone, lineLight, lineLight, lineNone}:       '┐',
+	{lineNone, lineLight, lineHeavy, lineNone}:       '┑',
+	{lineNone, lineHeavy, lineLight, lineNone}:       '┒',
+	{lineNone, lineHeavy, lineHeavy, lineNone}:       '┓',
+	{lineLight, lineNone, lineNone, lineLight}:       '└',
+	{lineLight, lineNone, lineNone, lineHeavy}:       '┕',
+	{lineHeavy, lineNone, lineNone, lineLight}:       '┖',
+	{lineHeavy, lineNone, lineNone, lineHeavy}:       '┗',
+	{lineLight, lineNone, lineLight, lineNone}:       '┘',
+	{lineLight, lineNone, lineHeavy, lineNone}:       '┙',
+	{lineHeavy, lineNone, lineLight, lineNone}:       '┚',
+	{lineHeavy, lineNone, lineHeavy, lineNone}:       '┛',
+	{lineLight, lineLight, lineNone, lineLight}:      '├',
+	{lineLight, lineLight, lineNone, lineHeavy}:      '┝',
+	{lineHeavy, lineLight, lineNone, lineLight}:      '┞',
+	{lineLight, lineHeavy, lineNone, lineLight}:      '┟',
+	{lineHeavy, lineHeavy, lineNone, lineLight}:      '┠',
+	{lineHeavy, lineLight, lineNone, lineHeavy}:      '┡',
+	{lineLight, lineHeavy, lineNone, lineHeavy}:      '┢',
+	{lineHeavy, lineHeavy, lineNone, lineHeavy}:      '┣',
+	{lineLight, lineLight, lineLight, lineNone}:      '┤',
+	{lineLight, lineLight, lineHeavy, lineNone}:      '┥',
+	{lineHeavy, lineLight, lineLight, lineNone}:      '┦',
+	{lineLight, lineHeavy, lineLight, lineNone}:      '┧',
+	{lineHeavy, lineHeavy, lineLight, lineNone}:      '┨',
+	{lineHeavy, lineLight, lineHeavy, lineNone}:      '┩',
+	{lineLight, lineHeavy, lineHeavy, lineNone}:      '┪',
+	{lineHeavy, lineHeavy, lineHeavy, lineNone}:      '┫',
+	{lineNone, lineLight, lineLight, lineLight}:      '┬',
+	{lineNone, lineLight, lineHeavy, lineLight}:      '┭',
+	{lineNone, lineLight, lineLight, lineHeavy}:      '┮',
+	{lineNone, lineLight, lineHeavy, lineHeavy}:      '┯',
+	{lineNone, lineHeavy, lineLight, lineLight}:      '┰',
+	{lineNone, lineHeavy, lineHeavy, lineLight}:      '┱',
+	{lineNone, lineHeavy, lineLight, lineHeavy}:      '┲',
+	{lineNone, lineHeavy, lineHeavy, lineHeavy}:      '┳',
+	{lineLight, lineNone, lineLight, lineLight}:      '┴',
+	{lineLight, lineNone, lineHeavy, lineLight}:      '┵',
+	{lineLight, lineNone, lineLight, lineHeavy}:      '┶',
+	{lineLight, lineNone, lineHeavy, lineHeavy}:      '┷',
+	{lineHeavy, lineNone, lineLight, lineLight}:      '┸',
+	{lineHeavy, lineNone, lineHeavy, lineLight}:      '┹',
+	{lineHeavy, lineNone, lineLight, lineHeavy}:      '┺',
+	{lineHeavy, lineNone, lineHeavy, lineHeavy}:      '┻',
+	{lineLight, lineLight, lineLight, lineLight}:     '┼',
+	{lineLight, lineLight, lineHeavy, lineLight}:     '┽',
+	{lineLight, lineLight, lineLight, lineHeavy}:     '┾',
+	{lineLight, lineLight, lineHeavy, lineHeavy}:     '┿',
+	{lineHeavy, lineLight, lineLight, lineLight}:     '╀',
+	{lineLight, lineHeavy, lineLight, lineLight}:     '╁',
+	{lineHeavy, lineHeavy, lineLight, lineLight}:     '╂',
+	{lineHeavy, lineLight, lineHeavy, lineLight}:     '╃',
+	{lineHeavy, lineLight, lineLight, lineHeavy}:     '╄',
+	{lineLight, lineHeavy, lineHeavy, lineLight}:     '╅',
+	{lineLight, lineHeavy, lineLight, lineHeavy}:     '╆',
+	{lineHeavy, lineLight, lineHeavy, lineHeavy}:     '╇',
+	{lineLight, lineHeavy, lineHeavy, lineHeavy}:     '╈',
+	{lineHeavy, lineHeavy, lineHeavy, lineLight}:     '╉',
+	{lineHeavy, lineHeavy, lineLight, lineHeavy}:     '╊',
+	{lineHeavy, lineHeavy, lineHeavy, lineHeavy}:     '╋',
+	{lineNone, lineNone, lineDouble, lineDouble}:     '═',
+	{lineDouble, lineDouble, lineNone, lineNone}:     '║',
+	{lineNone, lineLight, lineNone, lineDouble}:      '╒',
+	{lineNone, lineDouble, lineNone, lineLight}:      '╓',
+	{lineNone, lineDouble, lineNone, lineDouble}:     '╔',
+	{lineNone, lineLight, lineDouble, lineNone}:      '╕',
+	{lineNone, lineDouble, lineLight, lineNone}:      '╖',
+	{lineNone, lineDouble, lineDouble, lineNone}:     '╗',
+	{lineLight, lineNone, lineNone, lineDouble}:      '╘',
+	{lineDouble, lineNone, lineNone, lineLight}:      '╙',
+	{lineDouble, lineNone, lineNone, lineDouble}:     '╚',
+	{lineLight, lineNone, lineDouble, lineNone}:      '╛',
+	{lineDouble, lineNone, lineLight, lineNone}:      '╜',
+	{lineDouble, lineNone, lineDouble, lineNone}:     '╝',
+	{lineLight, lineLight, lineNone, lineDouble}:     '╞',
+	{lineDouble, lineDouble, lineNone, lineLight}:    '╟',
+	{lineDouble, lineDouble, lineNone, lineDouble}:   '╠',
+	{lineLight, lineLight, lineDouble, lineNone}:     '╡',
+	{lineDouble, lineDouble, lineLight, lineNone}:    '╢',
+	{lineDouble, lineDouble, lineDouble, lineNone}:   '╣',
+	{lineNone, lineLight, lineDouble, lineDouble}:    '╤',
+	{lineNone, lineDouble, lineLight, lineLight}:     '╥',
+	{lineNone, lineDouble, lineDouble, lineDouble}:   '╦',
+	{lineLight, lineNone, lineDouble, lineDouble}:    '╧',
+	{lineDouble, lineNone, lineLight, lineLight}:     '╨',
+	{lineDouble, lineNone, lineDouble, lineDouble}:   '╩',
+	{lineLight, lineLight, lineDouble, lineDouble}:   '╪',
+	{lineDouble, lineDouble, lineLight, lineLight}:   '╫',
+	{lineDouble, lineDouble, lineDouble, lineDouble}: '╬',
+	{lineNone, lineNone, lineLight, lineNone}:        '╴',
+	{lineLight, lineNone, lineNone, lineNone}:        '╵',
+	{lineNone, lineNone, lineNone, lineLight}:        '╶',
+	{lineNone, lineLight, lineNone, lineNone}:        '╷',
+	{lineNone, lineNone, lineHeavy, lineNone}:        '╸',
+	{lineHeavy, lineNone, lineNone, lineNone}:        '╹',
+	{lineNone, lineNone, lineNone, lineHeavy}:        '╺',
+	{lineNone, lineHeavy, lineNone, lineNone}:        '╻',
+	{lineNone, lineNone, lineLight, lineHeavy}:       '╼',
+	{lineLight, lineHeavy, lineNone, lineNone}:       '╽',
+	{lineNone, lineNone, lineHeavy, lineLight}:       '╾',
+	{lineHeavy, lineLight, lineNone, lineNone}:       '╿',
+}
@@ -0,0 +1,55 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import "testing"
+
+func TestJunctionRune(t *testing.T) {
+	g := &Gui{SupportOverlaps: true}
+	left := &View{name: "left", x0: 0, y0: 0, x1: 10, y1: 5, Visible: true, Frame: true, Overlaps: RIGHT}
+	right := &View{name: "right", x0: 10, y0: 0, x1: 20, y1: 5, Visible: true, Frame: true, Overlaps: LEFT}
+	g.views = []*View{left, right}
+
+	// same style on both sides keeps the usual runes
+	if r := g.junctionRune(right, g.frameRunes(right), right.x0, right.y0, BOTTOM|RIGHT); r != '┬' {
+		t.Errorf("expected '┬', got %q", r)
+	}
+
+	left.FrameRunes = FrameRunesHeavy()
+	if r := g.junctionRune(right, g.frameRunes(right), right.x0, right.y0, BOTTOM|RIGHT); r != '┭' {
+		t.Errorf("expected '┭', got %q", r)
+	}
+	if r := g.junctionRune(left, g.frameRunes(left), left.x1, left.y1, TOP|LEFT); r != '┹' {
+		t.Errorf("expected '┹', got %q", r)
+	}
+
+	// there is no rune with a double left arm and single other arms
+	left.FrameRunes = FrameRunesDouble()
+	if r := g.junctionRune(right, g.frameRunes(right), right.x0, right.y0, BOTTOM|RIGHT); r != '┬' {
+		t.Errorf("expected '┬', got %q", r)
+	}
+
+	// the focused frame replaces the frame of the current view
+	g.Highlight = true
+	g.currentView = left
+	left.SelFrameRunes = FrameRunesSingle()
+	if r := g.junctionRune(right, g.frameRunes(right), right.x0, right.y0, BOTTOM|RIGHT); r != '┬' {
+		t.Errorf("expected '┬', got %q", r)
+	}
+}
+
+func TestFrameRunesPresets(t *testing.T) {
+	runes := FrameRunesHeavy()
+	runes[0] = 'x'
+	if r := FrameRunesHeavy()[0]; r != '━' {
+		t.Errorf("expected the preset to be unchanged, got %q", r)
+	}
+	if r := GetFrameRunes("heavy")[0]; r != '━' {
+		t.Errorf("expected the preset to be unchanged, got %q", r)
+	}
+	if runes := GetFrameRunes("unknown"); runes != nil {
+		t.Errorf("expected no frame, got %q", runes)
+	}
+}
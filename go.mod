@@ -3,7 +3,10 @@ module github.com/awesome-gocui/gocui
 go 1.13
 
 require (
+	github.com/BurntSushi/toml v0.3.0
 	github.com/gdamore/tcell/v2 v2.4.0
 	github.com/mattn/go-runewidth v0.0.10
-	golang.org/x/text v0.3.3 // indirect
+	github.com/rivo/uniseg v0.1.0
+	golang.org/x/text v0.3.3
+	gopkg.in/yaml.v2 v2.4.0
 )
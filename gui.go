@@ -30,6 +30,14 @@ var (
 	// ErrUnknownView allows to assert if a View must be initialized.
 	ErrUnknownView = errors.New("unknown view")
 
+	// ErrViewExists is returned when a modal dialog is shown with the name
+	// of a view which is not a modal dialog.
+	ErrViewExists = errors.New("view already exists")
+
+	// ErrModalActive is returned by SetCurrentView when the focus can't be
+	// given to the view because a modal dialog is shown.
+	ErrModalActive = errors.New("modal dialog active")
+
 	// ErrQuit is used to decide if the MainLoop finished successfully.
 	ErrQuit = errors.New("quit")
 )
@@ -58,6 +66,16 @@ const (
 	// input and the option to retrieve the current conent
 	// See: SendKeyToSimulatedScreen, GetContentOfSimulatedScreen
 	OutputSimulator
+
+	// OutputAuto picks the best output mode for the terminal, based on
+	// COLORTERM, TERM, the terminfo database and NO_COLOR.
+	// See: DetectCapabilities, Gui.Capabilities
+	OutputAuto
+
+	// OutputMonochrome draws without colors. Text effects (bold, underline,
+	// reverse, ...) are kept, and are used to show the selection and the
+	// focused view. OutputAuto picks this mode when NO_COLOR is set.
+	OutputMonochrome
 )
 
 // Gui represents the whole User Interface, including the views, layouts
@@ -71,6 +89,11 @@ type Gui struct {
 	keybindings []*keybinding
 	maxX, maxY  int
 	outputMode  OutputMode
+	caps        Capabilities
+	theme       *Theme
+	baseTheme   *Theme // the colors before the first theme, see SetTheme
+	modals      []*modal
+	modalCount  int // used to name the dialogs
 	stop        chan struct{}
 	blacklist   []Key
 	testCounter int // used for testing synchronization
@@ -108,6 +131,20 @@ type Gui struct {
 	// SupportOverlaps is true when we allow for view edges to overlap with other
 	// view edges
 	SupportOverlaps bool
+
+	// If ModalDim is true, the views below a modal dialog are dimmed.
+	ModalDim bool
+
+	// If SupportBracketedPaste is true, which is the default, bracketed
+	// paste is enabled: the pasted text is received at once instead of as
+	// typed keys, and doesn't trigger the keybindings. It must be set
+	// before MainLoop.
+	SupportBracketedPaste bool
+
+	// OnPaste, if not nil, is called with the text pasted while the current
+	// view is not editable. The text is dropped if it is nil: the pasted
+	// text never triggers the keybindings.
+	OnPaste func(g *Gui, text string) error
 }
 
 // NewGui returns a new Gui object with a given output mode.
@@ -127,6 +164,18 @@ func NewGui(mode OutputMode, supportOverlaps bool) (*Gui, error) {
 
 	g := &Gui{}
 
+	g.caps = DetectCapabilities()
+	if mode != OutputSimulator {
+		// The screen knows better than the terminfo lookup, as it also
+		// handles the terminals which are not in the database.
+		if n := screen.Colors(); n > g.caps.Colors {
+			g.caps.Colors = n
+		}
+		g.caps.Mouse = g.caps.Mouse || screen.HasMouse()
+	}
+	if mode == OutputAuto {
+		mode = g.caps.OutputMode()
+	}
 	g.outputMode = mode
 
 	g.stop = make(chan struct{})
@@ -151,6 +200,7 @@ func NewGui(mode OutputMode, supportOverlaps bool) (*Gui, error) {
 	// SupportOverlaps is true when we allow for view edges to overlap with other
 	// view edges
 	g.SupportOverlaps = supportOverlaps
+	g.SupportBracketedPaste = true
 
 	return g, nil
 }
@@ -164,6 +214,17 @@ func (g *Gui) Close() {
 	screen.Fini()
 }
 
+// Capabilities returns the capabilities detected for the terminal.
+func (g *Gui) Capabilities() Capabilities {
+	return g.caps
+}
+
+// OutputMode returns the output mode in use. If the Gui was created with
+// OutputAuto, this is the mode which was picked for the terminal.
+func (g *Gui) OutputMode() OutputMode {
+	return g.outputMode
+}
+
 // Size returns the terminal's size.
 func (g *Gui) Size() (x, y int) {
 	return g.maxX, g.maxY
@@ -182,7 +243,7 @@ func (g *Gui) SetRune(x, y int, ch rune, fgColor, bgColor Attribute) error {
 	if x < 0 || y < 0 || x >= g.maxX || y >= g.maxY {
 		return errors.New("invalid point")
 	}
-	tcellSetCell(x, y, ch, fgColor, bgColor, g.outputMode)
+	tcellSetCell(x, y, ch, nil, fgColor, bgColor, g.outputMode)
 	return nil
 }
 
@@ -221,6 +282,7 @@ func (g *Gui) SetView(name string, x0, y0, x1, y1 int, overlaps byte) (*View, er
 	v := g.newView(name, x0, y0, x1, y1, g.outputMode)
 	v.BgColor, v.FgColor = g.BgColor, g.FgColor
 	v.SelBgColor, v.SelFgColor = g.SelBgColor, g.SelFgColor
+	g.applyTheme(v)
 	v.Overlaps = overlaps
 	g.views = append(g.views, v)
 	return v, ErrUnknownView
@@ -237,7 +299,8 @@ func (g *Gui) SetViewBeneath(name string, aboveViewName string, height int) (*Vi
 	return g.SetView(name, aboveView.x0, viewTop, aboveView.x1, viewTop+height-1, 0)
 }
 
-// SetViewOnTop sets the given view on top of the existing ones.
+// SetViewOnTop sets the given view on top of the existing ones with the
+// same ZIndex.
 func (g *Gui) SetViewOnTop(name string) (*View, error) {
 	for i, v := range g.views {
 		if v.name == name {
@@ -249,7 +312,8 @@ func (g *Gui) SetViewOnTop(name string) (*View, error) {
 	return nil, ErrUnknownView
 }
 
-// SetViewOnBottom sets the given view on bottom of the existing ones.
+// SetViewOnBottom sets the given view on bottom of the existing ones with
+// the same ZIndex.
 func (g *Gui) SetViewOnBottom(name string) (*View, error) {
 	for i, v := range g.views {
 		if v.name == name {
@@ -281,8 +345,9 @@ func (g *Gui) View(name string) (*View, error) {
 // error ErrUnknownView if a view in that position does not exist.
 func (g *Gui) ViewByPosition(x, y int) (*View, error) {
 	// traverse views in reverse order checking top views first
-	for i := len(g.views); i > 0; i-- {
-		v := g.views[i-1]
+	views := g.stackedViews()
+	for i := len(views); i > 0; i-- {
+		v := views[i-1]
 		if x > v.x0 && x < v.x1 && y > v.y0 && y < v.y1 {
 			return v, nil
 		}
@@ -306,16 +371,25 @@ func (g *Gui) DeleteView(name string) error {
 	for i, v := range g.views {
 		if v.name == name {
 			g.views = append(g.views[:i], g.views[i+1:]...)
+			g.removeModal(v)
 			return nil
 		}
 	}
 	return ErrUnknownView
 }
 
-// SetCurrentView gives the focus to a given view.
+// SetCurrentView gives the focus to a given view. While a modal dialog is
+// shown, it keeps the focus and ErrModalActive is returned with the view: a
+// view below the dialogs gets the focus when they are dismissed instead.
 func (g *Gui) SetCurrentView(name string) (*View, error) {
 	for _, v := range g.views {
 		if v.name == name {
+			if m := g.ModalView(); m != nil && v != m {
+				if !g.isModalView(v) {
+					g.modals[0].previous = v
+				}
+				return v, ErrModalActive
+			}
 			g.currentView = v
 			return v, nil
 		}
@@ -460,6 +534,7 @@ func (g *Gui) SetManager(managers ...Manager) {
 	g.managers = managers
 	g.currentView = nil
 	g.views = nil
+	g.modals = nil
 	g.keybindings = nil
 
 	go func() { g.gEvents <- gocuiEvent{Type: eventResize} }()
@@ -493,6 +568,9 @@ func (g *Gui) MainLoop() error {
 	if g.Mouse {
 		screen.EnableMouse()
 	}
+	if g.SupportBracketedPaste {
+		screen.EnablePaste()
+	}
 
 	if err := g.flush(); err != nil {
 		return err
@@ -552,6 +630,8 @@ func (g *Gui) handleEvent(ev *gocuiEvent) error {
 	switch ev.Type {
 	case eventKey, eventMouse:
 		return g.onKey(ev)
+	case eventPaste:
+		return g.onPaste(ev)
 	case eventTime:
 		g.testCounter++
 		return nil
@@ -583,16 +663,26 @@ func (g *Gui) flush() error {
 			return err
 		}
 	}
-	for _, v := range g.views {
+	g.layoutAnchors()
+	g.layoutModals()
+	for _, v := range g.stackedViews() {
 		if !v.Visible || v.y1 < v.y0 {
 			continue
 		}
+		if g.ModalDim && g.isModalView(v) {
+			tcellDim()
+		}
 		if v.Frame {
 			var fgColor, bgColor, frameColor Attribute
 			if g.Highlight && v == g.currentView {
 				fgColor = g.SelFgColor
 				bgColor = g.SelBgColor
 				frameColor = g.SelFrameColor
+				if g.outputMode == OutputMonochrome {
+					// Without colors, the focused view is shown with effects
+					fgColor |= AttrReverse | AttrBold
+					frameColor |= AttrBold
+				}
 			} else {
 				bgColor = g.BgColor
 				if v.TitleColor != ColorDefault {
@@ -613,20 +703,19 @@ func (g *Gui) flush() error {
 			if err := g.drawFrameCorners(v, frameColor, bgColor); err != nil {
 				return err
 			}
-			if v.Title != "" {
-				if err := g.drawTitle(v, fgColor, bgColor); err != nil {
-					return err
-				}
+			if err := g.drawLabels(v, EdgeTop, fgColor, bgColor); err != nil {
+				return err
 			}
-			if v.Subtitle != "" {
-				if err := g.drawSubtitle(v, fgColor, bgColor); err != nil {
-					return err
-				}
+			if err := g.drawLabels(v, EdgeBottom, fgColor, bgColor); err != nil {
+				return err
 			}
 		}
 		if err := g.draw(v); err != nil {
 			return err
 		}
+		if v.Shadow {
+			g.drawShadow(v)
+		}
 	}
 	screen.Show()
 	return nil
@@ -647,9 +736,9 @@ func (g *Gui) clear(fg, bg Attribute) (int, int) {
 func (g *Gui) drawFrameEdges(v *View, fgColor, bgColor Attribute) error {
 	runeH, runeV := '─', '│'
 	if g.ASCII {
-		runeH, runeV = '-', '|'
-	} else if len(v.FrameRunes) >= 2 {
-		runeH, runeV = v.FrameRunes[0], v.FrameRunes[1]
+		runeH, runeV = frameRunesASCII[0], frameRunesASCII[1]
+	} else if runes := g.frameRunes(v); len(runes) >= 2 {
+		runeH, runeV = runes[0], runes[1]
 	}
 
 	for x := v.x0 + 1; x < v.x1 && x < g.maxX; x++ {
@@ -689,9 +778,9 @@ func cornerRune(index byte) rune {
 	return []rune{' ', '│', '│', '│', '─', '┘', '┐', '┤', '─', '└', '┌', '├', '├', '┴', '┬', '┼'}[index]
 }
 
-// cornerCustomRune returns rune from `v.FrameRunes` slice. If the length of slice is less than 11
-// all the missing runes will be translated to the default `cornerRune()`
-func cornerCustomRune(v *View, index byte) rune {
+// cornerCustomRune returns rune from the `runes` slice (see `View.FrameRunes`). If the length of slice
+// is less than 11 all the missing runes will be translated to the default `cornerRune()`
+func cornerCustomRune(runes []rune, index byte) rune {
 	// Translate `cornerRune()` index
 	//  0    1    2    3    4    5    6    7    8    9    10   11   12   13   14   15
 	// ' ', '│', '│', '│', '─', '┘', '┐', '┤', '─', '└', '┌', '├', '├', '┴', '┬', '┼'
@@ -700,52 +789,52 @@ func cornerCustomRune(v *View, index byte) rune {
 	// '─', '│', '┌', '┐', '└', '┘', '├', '┤', '┬', '┴', '┼'
 	switch index {
 	case 1, 2, 3:
-		return v.FrameRunes[1]
+		return runes[1]
 	case 4, 8:
-		return v.FrameRunes[0]
+		return runes[0]
 	case 5:
-		return v.FrameRunes[5]
+		return runes[5]
 	case 6:
-		return v.FrameRunes[3]
+		return runes[3]
 	case 7:
-		if len(v.FrameRunes) < 8 {
+		if len(runes) < 8 {
 			break
 		}
-		return v.FrameRunes[7]
+		return runes[7]
 	case 9:
-		return v.FrameRunes[4]
+		return runes[4]
 	case 10:
-		return v.FrameRunes[2]
+		return runes[2]
 	case 11, 12:
-		if len(v.FrameRunes) < 7 {
+		if len(runes) < 7 {
 			break
 		}
-		return v.FrameRunes[6]
+		return runes[6]
 	case 13:
-		if len(v.FrameRunes) < 10 {
+		if len(runes) < 10 {
 			break
 		}
-		return v.FrameRunes[9]
+		return runes[9]
 	case 14:
-		if len(v.FrameRunes) < 9 {
+		if len(runes) < 9 {
 			break
 		}
-		return v.FrameRunes[8]
+		return runes[8]
 	case 15:
-		if len(v.FrameRunes) < 11 {
+		if len(runes) < 11 {
 			break
 		}
-		return v.FrameRunes[10]
+		return runes[10]
 	default:
 		return ' ' // cornerRune(0)
 	}
 	return cornerRune(index)
 }
 
-func corner(v *View, directions byte) rune {
-	index := v.Overlaps | directions
-	if len(v.FrameRunes) >= 6 {
-		return cornerCustomRune(v, index)
+func corner(runes []rune, overlaps byte, directions byte) rune {
+	index := overlaps | directions
+	if len(runes) >= 6 {
+		return cornerCustomRune(runes, index)
 	}
 	return cornerRune(index)
 }
@@ -764,15 +853,16 @@ func (g *Gui) drawFrameCorners(v *View, fgColor, bgColor Attribute) error {
 		return nil
 	}
 
+	runes := g.frameRunes(v)
 	runeTL, runeTR, runeBL, runeBR := '┌', '┐', '└', '┘'
-	if len(v.FrameRunes) >= 6 {
-		runeTL, runeTR, runeBL, runeBR = v.FrameRunes[2], v.FrameRunes[3], v.FrameRunes[4], v.FrameRunes[5]
+	if len(runes) >= 6 {
+		runeTL, runeTR, runeBL, runeBR = runes[2], runes[3], runes[4], runes[5]
 	}
 	if g.SupportOverlaps {
-		runeTL = corner(v, BOTTOM|RIGHT)
-		runeTR = corner(v, BOTTOM|LEFT)
-		runeBL = corner(v, TOP|RIGHT)
-		runeBR = corner(v, TOP|LEFT)
+		runeTL = g.junctionRune(v, runes, v.x0, v.y0, BOTTOM|RIGHT)
+		runeTR = g.junctionRune(v, runes, v.x1, v.y0, BOTTOM|LEFT)
+		runeBL = g.junctionRune(v, runes, v.x0, v.y1, TOP|RIGHT)
+		runeBR = g.junctionRune(v, runes, v.x1, v.y1, TOP|LEFT)
 	}
 	if g.ASCII {
 		runeTL, runeTR, runeBL, runeBR = '+', '+', '+', '+'
@@ -793,48 +883,6 @@ func (g *Gui) drawFrameCorners(v *View, fgColor, bgColor Attribute) error {
 	return nil
 }
 
-// drawTitle draws the title of the view.
-func (g *Gui) drawTitle(v *View, fgColor, bgColor Attribute) error {
-	if v.y0 < 0 || v.y0 >= g.maxY {
-		return nil
-	}
-
-	for i, ch := range v.Title {
-		x := v.x0 + i + 2
-		if x < 0 {
-			continue
-		} else if x > v.x1-2 || x >= g.maxX {
-			break
-		}
-		if err := g.SetRune(x, v.y0, ch, fgColor, bgColor); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-// drawSubtitle draws the subtitle of the view.
-func (g *Gui) drawSubtitle(v *View, fgColor, bgColor Attribute) error {
-	if v.y0 < 0 || v.y0 >= g.maxY {
-		return nil
-	}
-
-	start := v.x1 - 5 - len(v.Subtitle)
-	if start < v.x0 {
-		return nil
-	}
-	for i, ch := range v.Subtitle {
-		x := start + i
-		if x >= v.x1 {
-			break
-		}
-		if err := g.SetRune(x, v.y0, ch, fgColor, bgColor); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 // draw manages the cursor and calls the draw function of a view.
 func (g *Gui) draw(v *View) error {
 	completed := func(hideCursor bool) error {
@@ -861,13 +909,10 @@ func (g *Gui) draw(v *View) error {
 		curview.cy = 0
 	}
 
-	cursorX, cursorY, onScreen := curview.linesPosOnScreen(curview.cx, curview.cy)
+	x, y, onScreen := curview.cursorScreenPosition()
 	if !onScreen {
 		return completed(true)
 	}
-
-	x := curview.x0 + cursorX + 1 - curview.ox
-	y := curview.y0 + cursorY + 1 - curview.oy
 	screen.ShowCursor(x, y)
 
 	return completed(false)
@@ -886,6 +931,13 @@ func (g *Gui) onKey(ev *gocuiEvent) error {
 		if matched {
 			break
 		}
+		if v := g.currentView; v != nil && v.Editable && v.Editor != nil && v.SingleLine && Key(ev.Key) == KeyEnter && ev.Ch == 0 {
+			submitEditor(v.Editor, v)
+			if v.OnSubmit != nil {
+				return v.OnSubmit(g, v)
+			}
+			break
+		}
 		if g.currentView != nil && g.currentView.Editable && g.currentView.Editor != nil {
 			g.currentView.Editor.Edit(g.currentView, Key(ev.Key), ev.Ch, Modifier(ev.Mod))
 		}
@@ -897,8 +949,26 @@ func (g *Gui) onKey(ev *gocuiEvent) error {
 		if err != nil {
 			break
 		}
-		if err := v.SetCursor(mx-v.x0-1+v.ox, my-v.y0-1+v.oy); err != nil {
-			return err
+		if m := g.ModalView(); m != nil && v != m {
+			break // the modal dialog receives all the events
+		}
+		col := mx - v.x0 - 1 - v.gutterWidth() + v.ox
+		if col < 0 {
+			col = 0 // on the frame or the gutter
+		}
+		x, y := v.bufferPosition(col, my-v.y0-1+v.oy)
+		if v.Editable && ev.Key == MouseLeft && ev.Mod&ModAlt != 0 {
+			// Alt+click adds a cursor, a click removes them
+			if err := v.AddCursor(x, y); err != nil {
+				return err
+			}
+		} else {
+			if v.Editable && ev.Key == MouseLeft {
+				v.ClearCursors()
+			}
+			if err := v.SetCursor(x, y); err != nil {
+				return err
+			}
 		}
 		if _, err := g.execKeybindings(v, ev); err != nil {
 			return err
@@ -908,6 +978,20 @@ func (g *Gui) onKey(ev *gocuiEvent) error {
 	return nil
 }
 
+// onPaste manages paste events. The text is pasted at once in the current
+// view if it is editable: the keybindings and OnSubmit are not called. Else
+// it is passed to OnPaste.
+func (g *Gui) onPaste(ev *gocuiEvent) error {
+	if v := g.currentView; v != nil && v.Editable && v.Editor != nil {
+		pasteText(v.Editor, v, ev.Text)
+		return nil
+	}
+	if g.OnPaste != nil {
+		return g.OnPaste(g, normalizeNewlines(ev.Text))
+	}
+	return nil
+}
+
 // execKeybindings executes the keybinding handlers that match the passed view
 // and event. The value of matched is true if there is a match and no errors.
 func (g *Gui) execKeybindings(v *View, ev *gocuiEvent) (matched bool, err error) {
@@ -926,7 +1010,8 @@ func (g *Gui) execKeybindings(v *View, ev *gocuiEvent) (matched bool, err error)
 			return g.execKeybinding(v, kb)
 		}
 
-		if kb.viewName == "" && (((v != nil && !v.Editable) || kb.ch == 0) || v == nil) {
+		// the global keybindings don't fire while a modal dialog is shown
+		if kb.viewName == "" && g.ModalView() == nil && (((v != nil && !v.Editable) || kb.ch == 0) || v == nil) {
 			globalKb = kb
 		}
 	}
@@ -0,0 +1,162 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"strconv"
+)
+
+// LineNumbers selects the line numbers drawn in the gutter of a view.
+type LineNumbers int
+
+// Line number modes.
+const (
+	// LineNumbersNone draws no line numbers.
+	LineNumbersNone LineNumbers = iota
+
+	// LineNumbersAbsolute draws the number of each line, starting at 1.
+	LineNumbersAbsolute
+
+	// LineNumbersRelative draws the distance of each line to the line of
+	// the cursor, which shows its own number.
+	LineNumbersRelative
+)
+
+// Sign is a marker drawn in the sign column of the gutter, next to a line
+// (an error, a bookmark, ...). If the colors are ColorDefault, the colors
+// of the gutter are used.
+type Sign struct {
+	Rune             rune
+	FgColor, BgColor Attribute
+}
+
+// SetSign sets the sign drawn next to the line y of the buffer. Signs are
+// attached to line indexes: they don't move when lines are inserted or
+// deleted. SignColumn must be true for the signs to be drawn.
+func (v *View) SetSign(y int, s Sign) error {
+	if y < 0 {
+		return ErrInvalidPoint
+	}
+	if v.signs == nil {
+		v.signs = make(map[int]Sign)
+	}
+	v.signs[y] = s
+	return nil
+}
+
+// ClearSign removes the sign of the line y of the buffer.
+func (v *View) ClearSign(y int) {
+	delete(v.signs, y)
+}
+
+// ClearSigns removes all the signs of the view.
+func (v *View) ClearSigns() {
+	v.signs = nil
+}
+
+// gutterWidth returns the number of columns of the gutter, as computed by
+// the last call to updateGutterWidth.
+func (v *View) gutterWidth() int {
+	return v.gutterCols
+}
+
+// updateGutterWidth computes the number of columns of the gutter, once per
+// draw. The view is redrawn if it changes, as the lines are wrapped in the
+// columns left.
+func (v *View) updateGutterWidth() {
+	w := 0
+	if v.SignColumn {
+		w++
+	}
+	if v.LineNumbers != LineNumbersNone {
+		w += v.lineNumberWidth() + 1
+	}
+	if w != v.gutterCols {
+		v.gutterCols = w
+		v.tainted = true
+	}
+}
+
+// lineNumberWidth returns the number of columns of the line numbers.
+func (v *View) lineNumberWidth() int {
+	n := len(v.lines)
+	if n < 1 {
+		n = 1
+	}
+	return len(strconv.Itoa(n))
+}
+
+// lineNumber returns the number drawn next to the line y of the buffer.
+func (v *View) lineNumber(y int) int {
+	if v.LineNumbers == LineNumbersRelative && y != v.cy {
+		if y < v.cy {
+			return v.cy - y
+		}
+		return y - v.cy
+	}
+	return y + 1
+}
+
+// drawGutter draws the gutter of the view next to the rows drawn by the
+// last call to draw.
+func (v *View) drawGutter() {
+	w := v.gutterWidth()
+	if w == 0 {
+		return
+	}
+	_, maxY := v.Size()
+
+	fgColor, bgColor := v.GutterFgColor, v.GutterBgColor
+	if fgColor == ColorDefault {
+		fgColor = v.FgColor
+	}
+	if bgColor == ColorDefault {
+		bgColor = v.BgColor
+	}
+
+	numWidth := v.lineNumberWidth()
+	x0, y0 := v.x0+1+v.PaddingX, v.y0+1+v.PaddingY
+	for y := 0; y < maxY; y++ {
+		line := -1
+		if y < len(v.gutterRows) {
+			line = v.gutterRows[y]
+		}
+
+		x := x0
+		if v.SignColumn {
+			ch, fg, bg := ' ', fgColor, bgColor
+			if s, ok := v.signs[line]; ok && line >= 0 {
+				ch = s.Rune
+				if s.FgColor != ColorDefault {
+					fg = s.FgColor
+				}
+				if s.BgColor != ColorDefault {
+					bg = s.BgColor
+				}
+			}
+			tcellSetCell(x, y0+y, ch, nil, fg, bg, v.outMode)
+			x++
+		}
+
+		if v.LineNumbers == LineNumbersNone {
+			continue
+		}
+		text := ""
+		fg := fgColor
+		if line >= 0 {
+			text = strconv.Itoa(v.lineNumber(line))
+			if line == v.cy {
+				fg |= AttrBold
+			}
+		}
+		for i := 0; i <= numWidth; i++ {
+			ch := ' '
+			if j := i - (numWidth - len(text)); i < numWidth && j >= 0 {
+				ch = rune(text[j])
+			}
+			tcellSetCell(x+i, y0+y, ch, nil, fg, bgColor, v.outMode)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// History is a list of entries typed in an input, the most recent last. An
+// entry added again is moved to the end, and the oldest entries are dropped
+// beyond MaxSize. If the history was loaded from a file, the file is written
+// each time an entry is added.
+type History struct {
+	entries []string
+	path    string
+
+	// MaxSize is the maximum number of entries, 0 meaning no limit.
+	MaxSize int
+}
+
+// NewHistory returns an empty history of at most maxSize entries.
+func NewHistory(maxSize int) *History {
+	return &History{MaxSize: maxSize}
+}
+
+// LoadHistory returns a history of at most maxSize entries, read from the
+// file at path, one entry per line. The file doesn't need to exist: it is
+// created when the first entry is added.
+func LoadHistory(path string, maxSize int) (*History, error) {
+	h := &History{path: path, MaxSize: maxSize}
+	b, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range strings.Split(string(b), "\n") {
+		if entry != "" {
+			h.add(entry)
+		}
+	}
+	return h, nil
+}
+
+// Entries returns the entries of the history, the most recent last.
+func (h *History) Entries() []string {
+	return h.entries
+}
+
+// Add adds an entry to the history, and writes the file of the history if
+// it was loaded from a file. Newlines are replaced by spaces, and empty
+// entries are ignored.
+func (h *History) Add(entry string) error {
+	entry = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(entry)
+	if strings.TrimSpace(entry) == "" {
+		return nil
+	}
+	h.add(entry)
+	return h.Save()
+}
+
+// add adds an entry, removing its previous occurrence and the entries
+// beyond the maximum size.
+func (h *History) add(entry string) {
+	for i, e := range h.entries {
+		if e == entry {
+			h.entries = append(h.entries[:i], h.entries[i+1:]...)
+			break
+		}
+	}
+	h.entries = append(h.entries, entry)
+	if h.MaxSize > 0 && len(h.entries) > h.MaxSize {
+		h.entries = h.entries[len(h.entries)-h.MaxSize:]
+	}
+}
+
+// Save writes the file of the history, if it was loaded from a file.
+func (h *History) Save() error {
+	if h.path == "" {
+		return nil
+	}
+	var b strings.Builder
+	for _, entry := range h.entries {
+		b.WriteString(entry)
+		b.WriteByte('\n')
+	}
+	return ioutil.WriteFile(h.path, []byte(b.String()), 0600)
+}
+
+// HistoryEditor is an Editor recalling the entries of a History in a single
+// line view. The up and down arrows recall the previous and the next
+// entries, the text being edited is restored after the most recent one.
+// Ctrl+R starts a reverse incremental search: the typed runes are searched
+// in the entries, from the most recent, and Ctrl+R again searches an older
+// entry. The search is shown in the subtitle of the view. Esc or Ctrl+G
+// cancel it, the other keys accept the entry found.
+//
+// The other keys are sent to Editor. The entries are not added by the
+// editor: the OnSubmit function of the view should add them.
+type HistoryEditor struct {
+	History *History
+
+	// Editor edits the text, DefaultEditor is used if it is nil.
+	Editor Editor
+
+	// index is the index of the entry shown, len(entries) for the text
+	// being edited, which is saved in draft
+	index int
+	draft string
+
+	// shown is the text set in the view, to detect that it was changed
+	// by something else than the editor
+	shown string
+
+	// searching is true during a search, for query, and subtitle is the
+	// subtitle of the view before the search
+	searching bool
+	query     string
+	subtitle  string
+}
+
+// NewHistoryEditor returns an editor recalling the entries of h. A view
+// needs its own editor.
+func NewHistoryEditor(h *History) *HistoryEditor {
+	return &HistoryEditor{History: h, index: len(h.entries)}
+}
+
+// Edit recalls the entries or searches them, or sends the key to the
+// editor.
+func (e *HistoryEditor) Edit(v *View, key Key, ch rune, mod Modifier) {
+	e.sync(v)
+
+	if e.searching {
+		if e.search(v, key, ch, mod) {
+			return
+		}
+		e.endSearch(v)
+	}
+
+	switch {
+	case key == KeyArrowUp && mod == ModNone:
+		e.recall(v, e.index-1)
+	case key == KeyArrowDown && mod == ModNone:
+		e.recall(v, e.index+1)
+	case key == KeyCtrlR:
+		e.searching, e.query, e.subtitle = true, "", v.Subtitle
+		e.draft = v.Buffer()
+		e.showSearch(v, true)
+	default:
+		e.editor().Edit(v, key, ch, mod)
+		e.edited(v)
+	}
+}
+
+// Paste adds the text to the query during a search, or sends it to the
+// editor.
+func (e *HistoryEditor) Paste(v *View, text string) {
+	e.sync(v)
+	if e.searching {
+		e.query += strings.Replace(normalizeNewlines(text), "\n", " ", -1)
+		e.find(v, e.index)
+		return
+	}
+	pasteText(e.editor(), v, text)
+	e.edited(v)
+}
+
+// sync stops recalling entries if the text of v was changed by something
+// else than the editor.
+func (e *HistoryEditor) sync(v *View) {
+	entries := e.History.entries
+	changed := v.Buffer() != e.shown
+	if e.index > len(entries) || changed && (e.index < len(entries) || e.searching) {
+		// the text was set or submitted, or entries were dropped
+		e.reset(v)
+	}
+}
+
+// edited keeps the text of v edited by the editor.
+func (e *HistoryEditor) edited(v *View) {
+	if e.index == len(e.History.entries) {
+		e.draft = v.Buffer()
+	}
+	e.shown = v.Buffer()
+}
+
+// submit tells the editor that v is submitted.
+func (e *HistoryEditor) submit(v *View) {
+	submitEditor(e.editor(), v)
+}
+
+// editor returns the editor editing the text.
+func (e *HistoryEditor) editor() Editor {
+	if e.Editor == nil {
+		return DefaultEditor
+	}
+	return e.Editor
+}
+
+// reset stops recalling entries, keeping the text of v.
+func (e *HistoryEditor) reset(v *View) {
+	if e.searching {
+		v.Subtitle = e.subtitle
+	}
+	e.index, e.searching = len(e.History.entries), false
+	e.draft, e.shown = v.Buffer(), v.Buffer()
+}
+
+// recall shows the i-th entry in v, or the text being edited after the
+// last entry.
+func (e *HistoryEditor) recall(v *View, i int) {
+	entries := e.History.entries
+	if i < 0 || i > len(entries) {
+		return
+	}
+	if e.index == len(entries) {
+		e.draft = v.Buffer()
+	}
+	e.index = i
+	if i == len(entries) {
+		e.show(v, e.draft)
+	} else {
+		e.show(v, entries[i])
+	}
+}
+
+// show sets the text of v.
+func (e *HistoryEditor) show(v *View, text string) {
+	v.setText(text)
+	e.shown = v.Buffer()
+}
+
+// search handles a key during a search. It returns false if the key ends
+// the search and must be handled by the editor.
+func (e *HistoryEditor) search(v *View, key Key, ch rune, mod Modifier) bool {
+	switch {
+	case ch != 0 && mod == ModNone:
+		e.query += string(ch)
+		e.find(v, e.index)
+	case key == KeySpace:
+		e.query += " "
+		e.find(v, e.index)
+	case key == KeyBackspace || key == KeyBackspace2:
+		if r := []rune(e.query); len(r) > 0 {
+			e.query = string(r[:len(r)-1])
+		}
+		e.find(v, len(e.History.entries))
+	case key == KeyCtrlR:
+		e.find(v, e.index-1)
+	case key == KeyEsc || key == KeyCtrlG:
+		e.index = len(e.History.entries)
+		e.show(v, e.draft)
+		e.endSearch(v)
+	default:
+		return false
+	}
+	return true
+}
+
+// find shows the most recent entry containing the query, starting from the
+// from-th one.
+func (e *HistoryEditor) find(v *View, from int) {
+	entries := e.History.entries
+	if from >= len(entries) {
+		from = len(entries) - 1
+	}
+	for i := from; i >= 0; i-- {
+		if strings.Contains(entries[i], e.query) {
+			e.index = i
+			e.show(v, entries[i])
+			e.showSearch(v, true)
+			return
+		}
+	}
+	e.showSearch(v, e.query == "")
+}
+
+// showSearch shows the query in the subtitle of v.
+func (e *HistoryEditor) showSearch(v *View, found bool) {
+	if found {
+		v.Subtitle = "(reverse-i-search)`" + e.query + "'"
+	} else {
+		v.Subtitle = "(failed reverse-i-search)`" + e.query + "'"
+	}
+}
+
+// endSearch ends the search, keeping the entry found.
+func (e *HistoryEditor) endSearch(v *View) {
+	e.searching = false
+	v.Subtitle = e.subtitle
+}
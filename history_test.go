@@ -0,0 +1,83 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHistoryFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gocui")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "history")
+
+	h, err := LoadHistory(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range []string{"ls", "cd /", "ls", "make\ntest", "  ", "git status"} {
+		if err := h.Add(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the duplicates are moved to the end, and the oldest entries dropped
+	h, err = LoadHistory(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"ls", "make test", "git status"}
+	if got := h.Entries(); !equalStrings(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestHistoryEditor(t *testing.T) {
+	h := NewHistory(0)
+	for _, entry := range []string{"git log", "ls", "git status"} {
+		if err := h.Add(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	v := (&Gui{}).newView("v", 0, 0, 20, 2, OutputNormal)
+	v.SingleLine = true
+	e := NewHistoryEditor(h)
+
+	e.Edit(v, 0, 'x', ModNone)
+	e.Edit(v, KeyArrowUp, 0, ModNone)
+	e.Edit(v, KeyArrowUp, 0, ModNone)
+	if got := v.Buffer(); got != "ls" {
+		t.Errorf("expected %q, got %q", "ls", got)
+	}
+	// the text being edited is restored
+	e.Edit(v, KeyArrowDown, 0, ModNone)
+	e.Edit(v, KeyArrowDown, 0, ModNone)
+	if got := v.Buffer(); got != "x" {
+		t.Errorf("expected %q, got %q", "x", got)
+	}
+
+	e.Edit(v, KeyCtrlR, 0, ModNone)
+	e.Edit(v, 0, 'g', ModNone)
+	e.Edit(v, 0, 'i', ModNone)
+	if got := v.Buffer(); got != "git status" || v.Subtitle != "(reverse-i-search)`gi'" {
+		t.Errorf("expected the last entry to be found, got %q with %q", got, v.Subtitle)
+	}
+	e.Edit(v, KeyCtrlR, 0, ModNone)
+	if got := v.Buffer(); got != "git log" {
+		t.Errorf("expected an older entry to be found, got %q", got)
+	}
+	// the entry found is accepted by a key which is not a rune
+	e.Edit(v, KeyArrowRight, 0, ModNone)
+	e.Edit(v, 0, '!', ModNone)
+	if got := v.Buffer(); got != "git log!" || v.Subtitle != "" {
+		t.Errorf("expected the entry to be edited, got %q with %q", got, v.Subtitle)
+	}
+}
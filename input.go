@@ -0,0 +1,53 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+// wraps reports whether the lines of the view are wrapped.
+func (v *View) wraps() bool {
+	return v.Wrap && !v.SingleLine
+}
+
+// isEmpty reports whether the buffer of the view is empty.
+func (v *View) isEmpty() bool {
+	for _, line := range v.lines {
+		if len(line) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// showsPlaceholder reports whether the placeholder is drawn instead of the
+// content.
+func (v *View) showsPlaceholder() bool {
+	return v.Placeholder != "" && len(v.lines) <= 1 && v.isEmpty()
+}
+
+// drawPlaceholder draws the placeholder on the first row of the view. It is
+// not masked, unlike the content.
+func (v *View) drawPlaceholder() {
+	maxX, maxY := v.Size()
+	if maxY < 1 {
+		return
+	}
+	col := 0
+	for _, c := range textCells(v.Placeholder, v.PlaceholderColor, v.BgColor) {
+		if col+c.width() > maxX {
+			break
+		}
+		tcellSetCell(v.x0+col+1+v.PaddingX+v.gutterWidth(), v.y0+1+v.PaddingY, c.chr, c.combining, c.fgColor, c.bgColor, v.outMode)
+		col += c.width()
+	}
+}
+
+// setText replaces the buffer with a single line of text, and moves the
+// cursor to its end.
+func (v *View) setText(text string) {
+	v.lines = [][]cell{textCells(text, v.FgColor, v.BgColor)}
+	v.cursors = nil
+	v.tainted = true
+	v.ox, v.cx, v.cy = 0, 0, 0
+	v.MoveCursor(len(v.lines[0]), 0)
+}
@@ -0,0 +1,104 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestViewSingleLine(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var submitted string
+	g.SetManagerFunc(func(g *Gui) error {
+		v, err := g.SetView("input", 0, 0, 6, 2, 0)
+		if err != nil && !errors.Is(err, ErrUnknownView) {
+			return err
+		}
+		if errors.Is(err, ErrUnknownView) {
+			v.Editable = true
+			v.SingleLine = true
+			v.Placeholder = "Search"
+			v.Mask = '*'
+			v.OnSubmit = func(g *Gui, v *View) error {
+				submitted = v.Buffer()
+				return nil
+			}
+			if _, err := g.SetCurrentView("input"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	// the placeholder is not masked
+	for x, r := range "Searc" {
+		if got, _ := g.Rune(x+1, 1); got != r {
+			t.Errorf("column %d: expected %q, got %q", x, r, got)
+		}
+	}
+
+	testingScreen.SendStringAsKeys("abcdefgh")
+	testingScreen.SendKeySync(KeyArrowUp)
+	testingScreen.SendKeySync(KeyEnter)
+	testingScreen.WaitSync()
+
+	if submitted != "abcdefgh" {
+		t.Errorf("expected %q to be submitted, got %q", "abcdefgh", submitted)
+	}
+	v, err := g.View("input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the text is scrolled to show the cursor at its end
+	if x, y := v.Cursor(); x != 8 || y != 0 || v.ox != 4 {
+		t.Errorf("expected the cursor at (8, 0) and the origin at 4, got (%d, %d) and %d", x, y, v.ox)
+	}
+
+	v.EditWrite('\n')
+	if got := v.Buffer(); got != "abcdefgh " {
+		t.Errorf("expected the newline to be written as a space, got %q", got)
+	}
+}
+
+func TestViewSingleLineNotEditable(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	submitted := false
+	g.SetManagerFunc(func(g *Gui) error {
+		v, err := g.SetView("input", 0, 0, 6, 2, 0)
+		if err != nil && !errors.Is(err, ErrUnknownView) {
+			return err
+		}
+		if errors.Is(err, ErrUnknownView) {
+			v.SingleLine = true
+			v.OnSubmit = func(g *Gui, v *View) error {
+				submitted = true
+				return nil
+			}
+			if _, err := g.SetCurrentView("input"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	// Enter is only a submission in an editable view
+	testingScreen.SendKeySync(KeyEnter)
+	if submitted {
+		t.Error("expected a view which is not editable not to be submitted")
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
+)
+
+// Alignment is the horizontal alignment of a frame label or of a table
+// column.
+type Alignment int
+
+// Alignments of the frame labels.
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+)
+
+// FrameEdge is the edge of the frame where a label is drawn.
+type FrameEdge int
+
+// Edges of the frame which can hold labels.
+const (
+	EdgeTop FrameEdge = iota
+	EdgeBottom
+)
+
+// ellipsis is drawn at the end of truncated labels.
+const ellipsis = '…'
+
+// LabelSpan is a part of a frame label drawn with its own colors. If the
+// colors are ColorDefault, the colors of the title are used.
+type LabelSpan struct {
+	Text             string
+	FgColor, BgColor Attribute
+}
+
+// FrameLabel is a text drawn on the top or bottom edge of the frame of a
+// view. Labels sharing an edge and an alignment are drawn next to each
+// other, in order. Labels that don't fit are truncated with an ellipsis,
+// the left aligned ones having the priority, then the right aligned ones.
+type FrameLabel struct {
+	Spans []LabelSpan
+	Edge  FrameEdge
+	Align Alignment
+
+	// margin is the number of edge runes left after a right aligned label
+	margin int
+}
+
+// subtitleMargin is the margin of the subtitle, which is drawn five columns
+// before the right edge of the view.
+const subtitleMargin = 4
+
+// NewFrameLabel returns a label made of a single span, drawn with the colors
+// of the title.
+func NewFrameLabel(edge FrameEdge, align Alignment, text string) FrameLabel {
+	return FrameLabel{
+		Spans: []LabelSpan{{Text: text, FgColor: ColorDefault, BgColor: ColorDefault}},
+		Edge:  edge,
+		Align: align,
+	}
+}
+
+// width returns the number of columns needed to draw the label.
+func (l FrameLabel) width() int {
+	w := 0
+	for _, s := range l.Spans {
+		w += runewidth.StringWidth(s.Text)
+	}
+	return w
+}
+
+// frameLabels returns the labels of v on the given edge, including the
+// title and the subtitle, which is the rightmost label.
+func (v *View) frameLabels(edge FrameEdge) []FrameLabel {
+	var labels []FrameLabel
+	if edge == EdgeTop && v.Title != "" {
+		labels = append(labels, NewFrameLabel(EdgeTop, v.TitleAlign, v.Title))
+	}
+	for _, l := range v.Labels {
+		if l.Edge == edge {
+			labels = append(labels, l)
+		}
+	}
+	if edge == EdgeTop && v.Subtitle != "" {
+		subtitle := NewFrameLabel(EdgeTop, AlignRight, v.Subtitle)
+		subtitle.margin = subtitleMargin
+		labels = append(labels, subtitle)
+	}
+	return labels
+}
+
+// drawLabels draws the labels of the given edge of the view.
+func (g *Gui) drawLabels(v *View, edge FrameEdge, fgColor, bgColor Attribute) error {
+	y := v.y0
+	if edge == EdgeBottom {
+		if v.y1 == v.y0 {
+			return nil
+		}
+		y = v.y1
+	}
+	if y < 0 || y >= g.maxY {
+		return nil
+	}
+
+	var left, center, right []FrameLabel
+	for _, l := range v.frameLabels(edge) {
+		switch l.Align {
+		case AlignCenter:
+			center = append(center, l)
+		case AlignRight:
+			right = append(right, l)
+		default:
+			left = append(left, l)
+		}
+	}
+
+	// Labels are drawn between the corners, leaving one edge rune on both
+	// sides, and separated by one edge rune.
+	lo, hi := v.x0+2, v.x1-2
+
+	x := lo
+	for _, l := range left {
+		x += g.drawLabel(l, x, y, hi-x+1, fgColor, bgColor) + 1
+	}
+	leftEnd := x
+
+	x = hi + 1
+	for i := len(right) - 1; i >= 0; i-- {
+		x -= right[i].margin
+		room := x - leftEnd
+		w := right[i].width()
+		if w > room {
+			w = room
+		}
+		if w <= 0 {
+			break
+		}
+		g.drawLabel(right[i], x-w, y, w, fgColor, bgColor)
+		x -= w + 1
+	}
+	rightStart := x
+
+	if len(center) == 0 {
+		return nil
+	}
+	w := len(center) - 1
+	for _, l := range center {
+		w += l.width()
+	}
+	// center on the frame if possible, or else in the remaining room
+	start := v.x0 + (v.x1-v.x0+1-w)/2
+	if start < leftEnd || start+w > rightStart {
+		start = leftEnd + (rightStart-leftEnd-w)/2
+		if start < leftEnd {
+			start = leftEnd
+		}
+	}
+	x = start
+	for _, l := range center {
+		x += g.drawLabel(l, x, y, rightStart-x, fgColor, bgColor) + 1
+	}
+	return nil
+}
+
+// drawLabel draws a label at (x, y) on at most maxWidth columns. If the
+// label is wider, it is truncated and ends with an ellipsis. It returns the
+// number of columns used.
+func (g *Gui) drawLabel(l FrameLabel, x, y, maxWidth int, fgColor, bgColor Attribute) int {
+	if maxWidth <= 0 {
+		return 0
+	}
+	truncate := l.width() > maxWidth
+
+	used := 0
+	for _, s := range l.Spans {
+		fg, bg := s.FgColor, s.BgColor
+		if fg == ColorDefault {
+			fg = fgColor
+		}
+		if bg == ColorDefault {
+			bg = bgColor
+		}
+		// draw grapheme clusters, so that combining runes are not split
+		gr := uniseg.NewGraphemes(s.Text)
+		for gr.Next() {
+			runes := gr.Runes()
+			w := runewidth.StringWidth(gr.Str())
+			if truncate && used+w > maxWidth-1 {
+				g.setLabelRune(x+used, y, ellipsis, nil, fg, bg)
+				return used + 1
+			}
+			g.setLabelRune(x+used, y, runes[0], runes[1:], fg, bg)
+			used += w
+		}
+	}
+	return used
+}
+
+// setLabelRune is like SetRune with combining runes, but ignores the points
+// out of the screen.
+func (g *Gui) setLabelRune(x, y int, ch rune, combining []rune, fgColor, bgColor Attribute) {
+	if x < 0 || x >= g.maxX || y < 0 || y >= g.maxY {
+		return
+	}
+	tcellSetCell(x, y, ch, combining, fgColor, bgColor, g.outputMode)
+}
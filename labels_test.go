@@ -0,0 +1,95 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// screenRow returns the runes drawn on the screen from x0 to x1 on row y.
+func screenRow(g *Gui, x0, x1, y int) string {
+	var row []rune
+	for x := x0; x <= x1; x++ {
+		r, _ := g.Rune(x, y)
+		row = append(row, r)
+	}
+	return string(row)
+}
+
+func TestFrameLabels(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetManagerFunc(func(g *Gui) error {
+		views := []struct {
+			name           string
+			x0, y0, x1, y1 int
+			setup          func(v *View)
+		}{
+			{"left", 0, 0, 20, 2, func(v *View) {
+				v.Title = "left"
+				v.Subtitle = "sub"
+			}},
+			{"center", 0, 3, 20, 5, func(v *View) {
+				v.Labels = []FrameLabel{
+					NewFrameLabel(EdgeTop, AlignCenter, "mid"),
+					NewFrameLabel(EdgeBottom, AlignRight, "end"),
+				}
+			}},
+			{"narrow", 0, 6, 8, 8, func(v *View) {
+				v.Title = "a long title"
+			}},
+			{"styled", 22, 0, 40, 2, func(v *View) {
+				v.Labels = []FrameLabel{{
+					Spans: []LabelSpan{{"ok", ColorGreen | AttrBold, ColorDefault}, {"!", ColorDefault, ColorDefault}},
+				}}
+			}},
+		}
+		for _, view := range views {
+			v, err := g.SetView(view.name, view.x0, view.y0, view.x1, view.y1, 0)
+			if err != nil {
+				if !errors.Is(err, ErrUnknownView) {
+					return err
+				}
+				view.setup(v)
+			}
+		}
+		return nil
+	})
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	rows := []struct {
+		x0, x1, y int
+		want      string
+	}{
+		// the subtitle ends five columns before the corner
+		{0, 20, 0, "┌─left──────sub─────┐"},
+		{0, 20, 3, "┌────────mid────────┐"},
+		{0, 20, 5, "└───────────────end─┘"},
+		{0, 8, 6, "┌─a lo…─┐"},
+		{22, 27, 0, "┌─ok!─"},
+	}
+	for _, row := range rows {
+		if got := screenRow(g, row.x0, row.x1, row.y); got != row.want {
+			t.Errorf("row %d: expected %q, got %q", row.y, row.want, got)
+		}
+	}
+
+	// the simulator drops the colors, but keeps the effects
+	_, _, st, _ := screen.GetContent(24, 0)
+	if _, _, attrs := st.Decompose(); attrs&tcell.AttrBold == 0 {
+		t.Error("expected the first span in bold")
+	}
+	_, _, st, _ = screen.GetContent(26, 0)
+	if _, _, attrs := st.Decompose(); attrs&tcell.AttrBold != 0 {
+		t.Error("expected the second span with the colors of the title")
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"sort"
+)
+
+// stackedViews returns the views in the order they are drawn, from the
+// bottom to the top: by increasing ZIndex, then in the order of g.views.
+// The modal dialogs are always on top.
+func (g *Gui) stackedViews() []*View {
+	views := make([]*View, len(g.views))
+	copy(views, g.views)
+	sort.SliceStable(views, func(i, j int) bool {
+		mi, mj := g.isModalView(views[i]), g.isModalView(views[j])
+		if mi != mj {
+			return mj
+		}
+		return views[i].ZIndex < views[j].ZIndex
+	})
+	return views
+}
+
+// setViewAbove moves v in the order of the views, so that it is drawn after
+// the views with the given names if they have the same ZIndex.
+func (g *Gui) setViewAbove(v *View, names []string) {
+	index, last := -1, -1
+	for i, w := range g.views {
+		if w == v {
+			index = i
+		}
+		for _, name := range names {
+			if w.name == name {
+				last = i
+			}
+		}
+	}
+	if index < 0 || index > last {
+		return
+	}
+	views := append(g.views[:index:index], g.views[index+1:last+1]...)
+	views = append(views, v)
+	g.views = append(views, g.views[last+1:]...)
+}
+
+// drawShadow draws the shadow of v on the cells on its right and below it.
+func (g *Gui) drawShadow(v *View) {
+	for y := v.y0 + 1; y <= v.y1+1; y++ {
+		g.shadeCell(v.x1+1, y, v.ShadowColor)
+	}
+	for x := v.x0 + 1; x <= v.x1; x++ {
+		g.shadeCell(x, v.y1+1, v.ShadowColor)
+	}
+}
+
+// shadeCell dims the cell at (x, y) and sets its background to bgColor,
+// unless it is ColorDefault. Points out of the screen are ignored.
+func (g *Gui) shadeCell(x, y int, bgColor Attribute) {
+	if x < 0 || x >= g.maxX || y < 0 || y >= g.maxY {
+		return
+	}
+	tcellShadeCell(x, y, bgColor, g.outputMode)
+}
+
+// isTransparent reports whether c lets the content below a transparent view
+// show through: it is blank and has no background color of its own.
+func (c cell) isTransparent() bool {
+	return (c.chr == ' ' || c.chr == 0) && c.bgColor == ColorDefault
+}
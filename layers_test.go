@@ -0,0 +1,65 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestViewLayers(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetManagerFunc(func(g *Gui) error {
+		// "top" is created first but drawn last because of its ZIndex
+		if v, err := g.SetView("top", 2, 0, 8, 2, 0); err != nil {
+			if !errors.Is(err, ErrUnknownView) {
+				return err
+			}
+			v.ZIndex = 1
+			v.Transparent = true
+			v.Shadow = true
+			fmt.Fprint(v, "a b")
+		}
+		if v, err := g.SetView("bottom", 0, 0, 12, 5, 0); err != nil {
+			if !errors.Is(err, ErrUnknownView) {
+				return err
+			}
+			fmt.Fprint(v, "xxxxxxxxxx")
+		}
+		return nil
+	})
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	// the blank cells of "top" show the content of "bottom"
+	for x, r := range []rune{'a', 'x', 'b', 'x'} {
+		if got, _ := g.Rune(3+x, 1); got != r {
+			t.Errorf("column %d: expected %q, got %q", 3+x, r, got)
+		}
+	}
+
+	if v, err := g.ViewByPosition(4, 1); err != nil || v.Name() != "top" {
+		t.Errorf("expected the view on top at 4, 1, got %v", v)
+	}
+
+	// the shadow is on the right of and below the view
+	for _, p := range [][2]int{{9, 1}, {9, 3}, {5, 3}} {
+		_, _, st, _ := screen.GetContent(p[0], p[1])
+		if _, _, attrs := st.Decompose(); attrs&tcell.AttrDim == 0 {
+			t.Errorf("expected the cell %v to be shadowed", p)
+		}
+	}
+	_, _, st, _ := screen.GetContent(10, 1)
+	if _, _, attrs := st.Decompose(); attrs&tcell.AttrDim != 0 {
+		t.Error("expected the cell 10, 1 not to be shadowed")
+	}
+}
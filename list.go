@@ -0,0 +1,367 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"strings"
+)
+
+// ListItem is an item of a List.
+type ListItem struct {
+	// Text is the text displayed for the item.
+	Text string
+
+	// Value is an arbitrary value attached to the item.
+	Value interface{}
+}
+
+// List is a Manager showing a list of items in a view, one of them being
+// selected. It is navigated with the arrow keys, Page Up, Page Down, Home,
+// End and the mouse. Typing filters the items: only those containing the
+// typed text are shown, Backspace and Esc edit or clear the filter, which
+// is shown in the subtitle of the view. With MultiSelect, Space marks and
+// unmarks the selected item. Enter submits the selection.
+//
+// The view is created by Layout with SetView, with the name of the list.
+// Its content must not be written, but it can be styled like any view.
+type List struct {
+	name           string
+	x0, y0, x1, y1 int
+	items          []ListItem
+
+	// filtered are the indexes of the items matching the filter, which are
+	// the rows of the view
+	filtered []int
+	filter   string
+
+	// cursor is the row of the selected item
+	cursor int
+	marked map[int]bool
+
+	// notified is the item selected when OnChange was last called, and
+	// changed is true if the marks changed since
+	notified int
+	changed  bool
+
+	// If MultiSelect is true, items can be marked with Space.
+	MultiSelect bool
+
+	// MarkRune is drawn before the marked items when MultiSelect is true.
+	MarkRune rune
+
+	// SelFgColor and SelBgColor are the colors of the selected item.
+	SelFgColor, SelBgColor Attribute
+
+	// OnChange, if not nil, is called by Layout when the selected item or
+	// the marks changed.
+	OnChange func(g *Gui, l *List) error
+
+	// OnSubmit, if not nil, is called when Enter is pressed.
+	OnSubmit func(g *Gui, l *List) error
+}
+
+// NewList returns a list of the given items, covering the region from
+// (x0, y0) to (x1, y1).
+func NewList(name string, x0, y0, x1, y1 int, items ...ListItem) *List {
+	l := &List{
+		name:       name,
+		x0:         x0,
+		y0:         y0,
+		x1:         x1,
+		y1:         y1,
+		marked:     make(map[int]bool),
+		notified:   -1,
+		MarkRune:   '*',
+		SelFgColor: ColorDefault | AttrReverse,
+		SelBgColor: ColorDefault,
+	}
+	l.SetItems(items)
+	return l
+}
+
+// SetDimensions moves the list to the region from (x0, y0) to (x1, y1).
+func (l *List) SetDimensions(x0, y0, x1, y1 int) {
+	l.x0, l.y0, l.x1, l.y1 = x0, y0, x1, y1
+}
+
+// Items returns the items of the list.
+func (l *List) Items() []ListItem {
+	return l.items
+}
+
+// SetItems replaces the items of the list. The marks are cleared, and the
+// first item is selected.
+func (l *List) SetItems(items []ListItem) {
+	l.items = items
+	l.marked = make(map[int]bool)
+	l.filtered, l.cursor = nil, 0
+	l.refilter()
+}
+
+// Selected returns the index of the selected item and the item. It returns
+// false if no item is shown.
+func (l *List) Selected() (int, ListItem, bool) {
+	if l.cursor >= len(l.filtered) {
+		return -1, ListItem{}, false
+	}
+	i := l.filtered[l.cursor]
+	return i, l.items[i], true
+}
+
+// SetSelected selects the item with the given index. It returns an error if
+// the item is not shown.
+func (l *List) SetSelected(i int) error {
+	for row, j := range l.filtered {
+		if j == i {
+			l.cursor = row
+			return nil
+		}
+	}
+	return errors.New("item not shown")
+}
+
+// Marked returns the indexes of the marked items, in order.
+func (l *List) Marked() []int {
+	var marked []int
+	for i := range l.items {
+		if l.marked[i] {
+			marked = append(marked, i)
+		}
+	}
+	return marked
+}
+
+// SetMarked marks or unmarks the item with the given index.
+func (l *List) SetMarked(i int, marked bool) {
+	if i < 0 || i >= len(l.items) || l.marked[i] == marked {
+		return
+	}
+	if marked {
+		l.marked[i] = true
+	} else {
+		delete(l.marked, i)
+	}
+	l.changed = true
+}
+
+// Filter returns the filter of the list.
+func (l *List) Filter() string {
+	return l.filter
+}
+
+// SetFilter shows only the items containing s, ignoring case. The selected
+// item stays selected if it matches.
+func (l *List) SetFilter(s string) {
+	l.filter = s
+	l.refilter()
+}
+
+// refilter computes the items matching the filter.
+func (l *List) refilter() {
+	selected, _, ok := l.Selected()
+	filter := strings.ToLower(l.filter)
+
+	l.filtered = l.filtered[:0]
+	l.cursor = 0
+	for i, item := range l.items {
+		if !strings.Contains(strings.ToLower(item.Text), filter) {
+			continue
+		}
+		if ok && i == selected {
+			l.cursor = len(l.filtered)
+		}
+		l.filtered = append(l.filtered, i)
+	}
+}
+
+// move moves the selection by n rows, within the items shown.
+func (l *List) move(n int) {
+	l.cursor += n
+	if l.cursor >= len(l.filtered) {
+		l.cursor = len(l.filtered) - 1
+	}
+	if l.cursor < 0 {
+		l.cursor = 0
+	}
+}
+
+// Layout creates the view of the list and draws the items. It calls
+// OnChange if the selection changed.
+func (l *List) Layout(g *Gui) error {
+	v, err := g.SetView(l.name, l.x0, l.y0, l.x1, l.y1, 0)
+	if err != nil {
+		if !errors.Is(err, ErrUnknownView) {
+			return err
+		}
+		// runes are sent to the editor, to type the filter
+		v.Editable = true
+		v.Editor = EditorFunc(l.edit)
+		if err := l.setKeybindings(g); err != nil {
+			return err
+		}
+	}
+	l.draw(v)
+
+	selected, _, _ := l.Selected()
+	if selected == l.notified && !l.changed {
+		return nil
+	}
+	l.notified, l.changed = selected, false
+	if l.OnChange != nil {
+		return l.OnChange(g, l)
+	}
+	return nil
+}
+
+// draw writes the items shown in v, and scrolls it to show the selected one.
+func (l *List) draw(v *View) {
+	width, height := v.Size()
+
+	lines := make([][]cell, 0, len(l.filtered))
+	for row, i := range l.filtered {
+		fg, bg := ColorDefault, ColorDefault
+		if row == l.cursor {
+			fg, bg = l.SelFgColor, l.SelBgColor
+		}
+		var line []cell
+		if l.MultiSelect {
+			mark := ' '
+			if l.marked[i] {
+				mark = l.MarkRune
+			}
+			line = append(line, cell{chr: mark, fgColor: fg, bgColor: bg}, cell{chr: ' ', fgColor: fg, bgColor: bg})
+		}
+		line = append(line, textCells(l.items[i].Text, fg, bg)...)
+		if row == l.cursor {
+			line = padCells(line, width, fg, bg)
+		}
+		lines = append(lines, line)
+	}
+	v.lines = lines
+	v.tainted = true
+
+	if l.cursor < v.oy {
+		v.oy = l.cursor
+	}
+	if height > 0 && l.cursor >= v.oy+height {
+		v.oy = l.cursor - height + 1
+	}
+	v.cx, v.cy = 0, l.cursor
+
+	v.Subtitle = ""
+	if l.filter != "" {
+		v.Subtitle = "/" + l.filter
+	}
+}
+
+// edit types the runes in the filter.
+func (l *List) edit(v *View, key Key, ch rune, mod Modifier) {
+	if ch != 0 && mod == 0 {
+		l.SetFilter(l.filter + string(ch))
+	}
+}
+
+// setKeybindings sets the keybindings of the view of the list.
+func (l *List) setKeybindings(g *Gui) error {
+	page := func(dir int) func(*Gui, *View) error {
+		return func(g *Gui, v *View) error {
+			_, height := v.Size()
+			if height < 1 {
+				height = 1
+			}
+			l.move(dir * height)
+			return nil
+		}
+	}
+	step := func(n int) func(*Gui, *View) error {
+		return func(*Gui, *View) error {
+			l.move(n)
+			return nil
+		}
+	}
+
+	bindings := []struct {
+		key     Key
+		handler func(*Gui, *View) error
+	}{
+		{KeyArrowUp, step(-1)},
+		{KeyArrowDown, step(1)},
+		{MouseWheelUp, step(-1)},
+		{MouseWheelDown, step(1)},
+		{KeyPgup, page(-1)},
+		{KeyPgdn, page(1)},
+		{KeyHome, l.onHome},
+		{KeyEnd, l.onEnd},
+		{KeySpace, l.onSpace},
+		{KeyBackspace, l.onBackspace},
+		{KeyBackspace2, l.onBackspace},
+		{KeyEsc, l.onEsc},
+		{KeyEnter, l.onEnter},
+		{MouseLeft, l.onClick},
+	}
+	for _, b := range bindings {
+		if err := g.SetKeybinding(l.name, b.key, ModNone, b.handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// onHome selects the first item.
+func (l *List) onHome(g *Gui, v *View) error {
+	l.cursor = 0
+	return nil
+}
+
+// onEnd selects the last item.
+func (l *List) onEnd(g *Gui, v *View) error {
+	l.move(len(l.filtered))
+	return nil
+}
+
+// onSpace marks or unmarks the selected item, or types a space in the
+// filter if the list is not multi select.
+func (l *List) onSpace(g *Gui, v *View) error {
+	if !l.MultiSelect {
+		l.SetFilter(l.filter + " ")
+		return nil
+	}
+	if i, _, ok := l.Selected(); ok {
+		l.SetMarked(i, !l.marked[i])
+	}
+	return nil
+}
+
+// onBackspace deletes the last rune of the filter.
+func (l *List) onBackspace(g *Gui, v *View) error {
+	if r := []rune(l.filter); len(r) > 0 {
+		l.SetFilter(string(r[:len(r)-1]))
+	}
+	return nil
+}
+
+// onEsc clears the filter.
+func (l *List) onEsc(g *Gui, v *View) error {
+	l.SetFilter("")
+	return nil
+}
+
+// onEnter submits the selection.
+func (l *List) onEnter(g *Gui, v *View) error {
+	if l.OnSubmit == nil {
+		return nil
+	}
+	return l.OnSubmit(g, l)
+}
+
+// onClick selects the clicked item. The cursor of the view was moved to
+// the click.
+func (l *List) onClick(g *Gui, v *View) error {
+	_, y := v.Cursor()
+	l.cursor = y
+	l.move(0)
+	return nil
+}
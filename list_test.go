@@ -0,0 +1,93 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"testing"
+)
+
+func TestListFilter(t *testing.T) {
+	l := NewList("list", 0, 0, 20, 5,
+		ListItem{Text: "apple"}, ListItem{Text: "Banana"}, ListItem{Text: "cherry"}, ListItem{Text: "banana split"})
+	if err := l.SetSelected(3); err != nil {
+		t.Fatal(err)
+	}
+
+	// the selected item stays selected when it matches
+	l.SetFilter("BAN")
+	if i, item, ok := l.Selected(); !ok || i != 3 || item.Text != "banana split" {
+		t.Errorf("expected item 3 to be selected, got %d %v", i, ok)
+	}
+	if err := l.SetSelected(0); err == nil {
+		t.Error("expected an error selecting a hidden item")
+	}
+
+	// otherwise the first item shown is selected
+	l.SetFilter("ch")
+	if i, _, _ := l.Selected(); i != 2 {
+		t.Errorf("expected item 2 to be selected, got %d", i)
+	}
+	l.SetFilter("none")
+	if _, _, ok := l.Selected(); ok {
+		t.Error("expected no item to be selected")
+	}
+
+	l.SetItems([]ListItem{{Text: "one"}})
+	if _, _, ok := l.Selected(); ok {
+		t.Error("expected the filter to hide the new items")
+	}
+}
+
+func TestListKeys(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := NewList("list", 0, 0, 20, 5, ListItem{Text: "one"}, ListItem{Text: "two"}, ListItem{Text: "three"})
+	l.MultiSelect = true
+	changes := 0
+	l.OnChange = func(g *Gui, l *List) error {
+		changes++
+		return nil
+	}
+	g.SetManager(l)
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	done := make(chan struct{})
+	g.Update(func(g *Gui) error {
+		defer close(done)
+		_, err := g.SetCurrentView("list")
+		return err
+	})
+	<-done
+	testingScreen.WaitSync()
+
+	testingScreen.SendKeySync(KeyArrowDown)
+	testingScreen.SendKeySync(KeySpace)
+	testingScreen.SendKeySync(KeyEnd)
+	testingScreen.SendKeySync(KeySpace)
+	testingScreen.WaitSync()
+
+	if i, _, _ := l.Selected(); i != 2 {
+		t.Errorf("expected item 2 to be selected, got %d", i)
+	}
+	if got := l.Marked(); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("expected items 1 and 2 to be marked, got %v", got)
+	}
+	// initial selection, moves and marks
+	if changes != 5 {
+		t.Errorf("expected 5 calls to OnChange, got %d", changes)
+	}
+
+	// the selected row is drawn after its mark
+	want := []rune{'*', ' ', 't', 'h', 'r'}
+	for x, r := range want {
+		if got, _ := g.Rune(x+1, 3); got != r {
+			t.Errorf("column %d: expected %q, got %q", x+1, r, got)
+		}
+	}
+}
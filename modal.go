@@ -0,0 +1,329 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// modal is a view shown as a modal dialog.
+type modal struct {
+	view *View
+
+	// width and height are the size of the content of the view, a height
+	// of 0 fits the content
+	width, height int
+
+	// previous is the view focused before the modal was shown
+	previous *View
+}
+
+// ShowModal dismisses the modal dialogs already shown and shows the view
+// with the given name as a modal dialog. See PushModal.
+func (g *Gui) ShowModal(name string, width, height int) (*View, error) {
+	for len(g.modals) > 0 {
+		top := g.modals[len(g.modals)-1].view
+		if top.name == name {
+			break
+		}
+		if err := g.DismissModal(top.name); err != nil {
+			return nil, err
+		}
+	}
+	return g.PushModal(name, width, height)
+}
+
+// PushModal shows the view with the given name as a modal dialog, above the
+// views and the modal dialogs already shown. The content of the view is
+// width columns wide and height rows high, or fits the content if height is
+// 0, and the view is centred on the screen. As with SetView, the error
+// ErrUnknownView is returned when the view is created. If a view which is
+// not a modal dialog has the given name, ErrViewExists is returned.
+//
+// While the modal is shown, it keeps the focus and receives all the key and
+// mouse events: the keybindings of the other views and the global ones
+// don't fire, and the views below are dimmed if ModalDim is true. The view
+// which had the focus gets it back when the modal is dismissed.
+func (g *Gui) PushModal(name string, width, height int) (*View, error) {
+	if width <= 0 {
+		return nil, fmt.Errorf("invalid modal width %d", width)
+	}
+
+	if m := g.modal(name); m != nil {
+		m.width, m.height = width, height
+		g.placeModal(m)
+		return m.view, nil
+	}
+
+	if _, err := g.View(name); err == nil {
+		return nil, ErrViewExists
+	}
+	v, err := g.SetView(name, 0, 0, 1, 1, 0)
+	if err != nil && !errors.Is(err, ErrUnknownView) {
+		return nil, err
+	}
+	m := &modal{view: v, width: width, height: height, previous: g.currentView}
+	g.modals = append(g.modals, m)
+	g.placeModal(m)
+	g.currentView = v
+	return v, err
+}
+
+// DismissModal dismisses the modal dialog with the given name: its view
+// and its keybindings are deleted, and the focus goes back to the view
+// which had it when the modal was shown.
+func (g *Gui) DismissModal(name string) error {
+	if g.modal(name) == nil {
+		return ErrUnknownView
+	}
+	g.DeleteKeybindings(name)
+	return g.DeleteView(name)
+}
+
+// ModalView returns the view of the modal dialog shown on top, or nil if no
+// modal is shown.
+func (g *Gui) ModalView() *View {
+	if len(g.modals) == 0 {
+		return nil
+	}
+	return g.modals[len(g.modals)-1].view
+}
+
+// modal returns the modal dialog with the given view name, or nil.
+func (g *Gui) modal(name string) *modal {
+	for _, m := range g.modals {
+		if m.view.name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// removeModal removes v from the modal dialogs, if it is one, and gives
+// the focus back to the view which had it when v was shown.
+func (g *Gui) removeModal(v *View) {
+	for i, m := range g.modals {
+		if m.view != v {
+			continue
+		}
+		if i == len(g.modals)-1 {
+			g.currentView = nil
+			if m.previous != nil {
+				if _, err := g.View(m.previous.name); err == nil {
+					g.currentView = m.previous
+				}
+			}
+		} else if g.modals[i+1].previous == v {
+			g.modals[i+1].previous = m.previous
+		}
+		g.modals = append(g.modals[:i], g.modals[i+1:]...)
+		return
+	}
+}
+
+// placeModal centres the view of a modal dialog on the screen.
+func (g *Gui) placeModal(m *modal) {
+	v := m.view
+	w := m.width
+	if w > g.maxX-2 {
+		w = g.maxX - 2
+	}
+	v.x0 = (g.maxX - w - 2) / 2
+	v.x1 = v.x0 + w + 1
+
+	h := m.height
+	if h <= 0 {
+		// the width is set, the wrapped lines can be counted
+		h = len(v.viewLines())
+		if h < 1 {
+			h = 1
+		}
+	}
+	if h > g.maxY-2 {
+		h = g.maxY - 2
+	}
+	v.y0 = (g.maxY - h - 2) / 2
+	v.y1 = v.y0 + h + 1
+	v.tainted = true
+}
+
+// layoutModals places the modal dialogs, and puts them on top of the other
+// views.
+func (g *Gui) layoutModals() {
+	for _, m := range g.modals {
+		g.placeModal(m)
+		// the view exists, SetViewOnTop can't fail
+		_, _ = g.SetViewOnTop(m.view.name)
+	}
+}
+
+// isModalView reports whether v is the view of a modal dialog.
+func (g *Gui) isModalView(v *View) bool {
+	for _, m := range g.modals {
+		if m.view == v {
+			return true
+		}
+	}
+	return false
+}
+
+// modalSize returns the content size of a dialog showing the given title
+// and message: as wide as the longest line, within the screen.
+func (g *Gui) modalSize(title, message string, minWidth int) int {
+	w := runewidth.StringWidth(title) + 4
+	if w < minWidth {
+		w = minWidth
+	}
+	for _, line := range strings.Split(message, "\n") {
+		if lw := runewidth.StringWidth(line); lw > w {
+			w = lw
+		}
+	}
+	if w > g.maxX-4 {
+		w = g.maxX - 4
+	}
+	if w < 1 {
+		w = 1
+	}
+	return w
+}
+
+// showMessage shows a modal dialog with the given title and message, and a
+// hint on the bottom edge of its frame.
+func (g *Gui) showMessage(kind, title, message, hint string) (*View, error) {
+	g.modalCount++
+	name := fmt.Sprintf("gocui-%s-%d", kind, g.modalCount)
+	width := g.modalSize(title, message, runewidth.StringWidth(hint)+4)
+
+	v, err := g.PushModal(name, width, 0)
+	if !errors.Is(err, ErrUnknownView) {
+		return nil, messageError(err)
+	}
+	v.Title = title
+	v.Wrap = true
+	v.WordWrap = true
+	v.Labels = []FrameLabel{NewFrameLabel(EdgeBottom, AlignRight, hint)}
+	fmt.Fprint(v, message)
+
+	// the height depends on the message
+	g.placeModal(g.modal(name))
+	return v, nil
+}
+
+// messageError returns the error of PushModal when it didn't create the
+// view of a dialog: the dialogs shown by Alert, Confirm and Prompt need a
+// new view.
+func messageError(err error) error {
+	if err == nil {
+		return ErrViewExists
+	}
+	return err
+}
+
+// Alert shows a modal dialog with a message. It is dismissed with Enter or
+// Esc, then done is called if it is not nil.
+func (g *Gui) Alert(title, message string, done func(*Gui) error) (*View, error) {
+	v, err := g.showMessage("alert", title, message, "Enter: OK")
+	if err != nil {
+		return nil, err
+	}
+
+	handler := func(g *Gui, v *View) error {
+		if err := g.DismissModal(v.name); err != nil {
+			return err
+		}
+		if done == nil {
+			return nil
+		}
+		return done(g)
+	}
+	for _, key := range []Key{KeyEnter, KeyEsc} {
+		if err := g.SetKeybinding(v.name, key, ModNone, handler); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// Confirm shows a modal dialog asking a yes or no question. It is answered
+// with Enter or y (yes), or with Esc or n (no), then done is called with the
+// answer if it is not nil.
+func (g *Gui) Confirm(title, message string, done func(g *Gui, ok bool) error) (*View, error) {
+	v, err := g.showMessage("confirm", title, message, "Enter: yes, Esc: no")
+	if err != nil {
+		return nil, err
+	}
+
+	answer := func(ok bool) func(*Gui, *View) error {
+		return func(g *Gui, v *View) error {
+			if err := g.DismissModal(v.name); err != nil {
+				return err
+			}
+			if done == nil {
+				return nil
+			}
+			return done(g, ok)
+		}
+	}
+	bindings := []struct {
+		key interface{}
+		ok  bool
+	}{{KeyEnter, true}, {'y', true}, {'Y', true}, {KeyEsc, false}, {'n', false}, {'N', false}}
+	for _, b := range bindings {
+		if err := g.SetKeybinding(v.name, b.key, ModNone, answer(b.ok)); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// Prompt shows a modal dialog with an editable line, initialized with
+// initial. The text is validated with Enter or cancelled with Esc, then
+// done is called with the text and whether it was validated, if it is not
+// nil.
+func (g *Gui) Prompt(title, initial string, done func(g *Gui, text string, ok bool) error) (*View, error) {
+	g.modalCount++
+	name := fmt.Sprintf("gocui-prompt-%d", g.modalCount)
+	hint := "Enter: OK, Esc: cancel"
+	width := g.modalSize(title, initial, 40)
+
+	v, err := g.PushModal(name, width, 1)
+	if !errors.Is(err, ErrUnknownView) {
+		return nil, messageError(err)
+	}
+	v.Title = title
+	v.Editable = true
+	v.SingleLine = true
+	v.Labels = []FrameLabel{NewFrameLabel(EdgeBottom, AlignRight, hint)}
+	fmt.Fprint(v, initial)
+	// SetCursor clamps the position to the end of the line
+	if err := v.SetCursor(len(initial), 0); err != nil {
+		return nil, err
+	}
+
+	answer := func(ok bool) func(*Gui, *View) error {
+		return func(g *Gui, v *View) error {
+			text := strings.TrimSuffix(v.Buffer(), "\n")
+			if err := g.DismissModal(v.name); err != nil {
+				return err
+			}
+			if done == nil {
+				return nil
+			}
+			return done(g, text, ok)
+		}
+	}
+	if err := g.SetKeybinding(name, KeyEnter, ModNone, answer(true)); err != nil {
+		return nil, err
+	}
+	if err := g.SetKeybinding(name, KeyEsc, ModNone, answer(false)); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
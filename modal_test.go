@@ -0,0 +1,123 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestModalFocusTrap(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetManagerFunc(func(g *Gui) error {
+		if _, err := g.SetView("main", 0, 0, 20, 10, 0); err != nil {
+			if !errors.Is(err, ErrUnknownView) {
+				return err
+			}
+			if _, err := g.SetCurrentView("main"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	globalFired := false
+	if err := g.SetKeybinding("", KeyF1, ModNone, func(*Gui, *View) error {
+		globalFired = true
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	answered, answer := false, true
+	done := make(chan struct{})
+	g.Update(func(g *Gui) error {
+		defer close(done)
+		v, err := g.Confirm("Quit", "Are you sure?", func(g *Gui, ok bool) error {
+			answered, answer = true, ok
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if g.CurrentView() != v || g.ModalView() != v {
+			t.Error("expected the dialog to have the focus")
+		}
+		// the focus stays on the dialog
+		if _, err := g.SetCurrentView("main"); !errors.Is(err, ErrModalActive) {
+			t.Errorf("expected ErrModalActive, got %v", err)
+		}
+		if g.CurrentView() != v {
+			t.Error("expected the dialog to keep the focus")
+		}
+		return nil
+	})
+	<-done
+	testingScreen.WaitSync()
+
+	testingScreen.SendKeySync(KeyF1)
+	if globalFired {
+		t.Error("expected the global keybinding not to fire under the dialog")
+	}
+
+	testingScreen.SendKeySync(KeyEsc)
+	if !answered || answer {
+		t.Errorf("expected the dialog to be answered no, got %v, %v", answered, answer)
+	}
+	if g.ModalView() != nil {
+		t.Error("expected the dialog to be dismissed")
+	}
+	if v := g.CurrentView(); v == nil || v.Name() != "main" {
+		t.Error("expected the focus to go back to the main view")
+	}
+
+	testingScreen.SendKeySync(KeyF1)
+	if !globalFired {
+		t.Error("expected the global keybinding to fire after the dialog")
+	}
+}
+
+func TestModalExistingView(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetManagerFunc(func(g *Gui) error {
+		for _, name := range []string{"main", "gocui-alert-1"} {
+			if _, err := g.SetView(name, 0, 0, 20, 10, 0); err != nil && !errors.Is(err, ErrUnknownView) {
+				return err
+			}
+		}
+		return nil
+	})
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	done := make(chan struct{})
+	g.Update(func(g *Gui) error {
+		defer close(done)
+		if _, err := g.PushModal("main", 10, 0); !errors.Is(err, ErrViewExists) {
+			t.Errorf("expected ErrViewExists, got %v", err)
+		}
+		// the name of the first alert is taken
+		if _, err := g.Alert("Error", "message", nil); !errors.Is(err, ErrViewExists) {
+			t.Errorf("expected ErrViewExists, got %v", err)
+		}
+		if v := g.ModalView(); v != nil {
+			t.Errorf("expected no modal dialog, got %q", v.Name())
+		}
+		return nil
+	})
+	<-done
+	testingScreen.WaitSync()
+}
@@ -0,0 +1,161 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestPaste(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	submitted := 0
+	g.SetManagerFunc(func(g *Gui) error {
+		v, err := g.SetView("input", 0, 0, 20, 2, 0)
+		if !errors.Is(err, ErrUnknownView) {
+			return err
+		}
+		v.Editable = true
+		v.SingleLine = true
+		v.OnSubmit = func(g *Gui, v *View) error {
+			submitted++
+			return nil
+		}
+		_, err = g.SetCurrentView("input")
+		return err
+	})
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	testingScreen.SendStringAsKeys(">")
+	testingScreen.SendPaste("foo\nbar")
+	testingScreen.WaitSync()
+
+	v, err := g.View("input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v.Buffer(); got != ">foo bar" {
+		t.Errorf("expected the newline to be pasted as a space, got %q", got)
+	}
+	if x, _ := v.Cursor(); x != 8 {
+		t.Errorf("expected the cursor after the pasted text, got %d", x)
+	}
+	if submitted != 0 {
+		t.Errorf("expected the newline not to submit, got %d submits", submitted)
+	}
+}
+
+func TestPasteText(t *testing.T) {
+	// DefaultEditor is an EditorFunc, its pasted text is inserted at once
+	// as with DefaultPasteEditor
+	if _, ok := DefaultEditor.(EditorFunc); !ok {
+		t.Error("expected DefaultEditor to be an EditorFunc")
+	}
+	if !isDefaultEditor(DefaultEditor) || !isDefaultEditor(DefaultPasteEditor) {
+		t.Error("expected DefaultEditor and DefaultPasteEditor to be detected")
+	}
+
+	v := (&Gui{}).newView("v", 0, 0, 20, 5, OutputNormal)
+	pasteText(DefaultEditor, v, "one\r\ntwo\x1b\tthree")
+	if got := v.Buffer(); got != "one\ntwo\tthree" {
+		t.Errorf("expected %q, got %q", "one\ntwo\tthree", got)
+	}
+	if x, y := v.Cursor(); x != 9 || y != 1 {
+		t.Errorf("expected the cursor at 9,1, got %d,%d", x, y)
+	}
+
+	// the editors which are not PasteEditors receive the keys
+	var keys []Key
+	editor := EditorFunc(func(v *View, key Key, ch rune, mod Modifier) {
+		keys = append(keys, key)
+		simpleEditor(v, key, ch, mod)
+	})
+	v = (&Gui{}).newView("v", 0, 0, 20, 5, OutputNormal)
+	pasteText(editor, v, "a b\nc")
+	if got := v.Buffer(); got != "a b\nc" {
+		t.Errorf("expected %q, got %q", "a b\nc", got)
+	}
+	want := []Key{0, KeySpace, 0, KeyEnter, 0}
+	if len(keys) != len(want) {
+		t.Fatalf("expected the keys %v, got %v", want, keys)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("expected the keys %v, got %v", want, keys)
+			break
+		}
+	}
+}
+
+func TestPasteNotEditable(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pasted string
+	g.OnPaste = func(g *Gui, text string) error {
+		pasted = text
+		return nil
+	}
+	triggered := false
+	g.SetManagerFunc(func(g *Gui) error {
+		_, err := g.SetView("list", 0, 0, 20, 5, 0)
+		if !errors.Is(err, ErrUnknownView) {
+			return err
+		}
+		_, err = g.SetCurrentView("list")
+		return err
+	})
+	if err := g.SetKeybinding("", 'q', ModNone, func(g *Gui, v *View) error {
+		triggered = true
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	testingScreen.SendPaste("quit\nnow")
+	testingScreen.WaitSync()
+	if triggered {
+		t.Error("expected the pasted text not to trigger the keybindings")
+	}
+	if pasted != "quit\nnow" {
+		t.Errorf("expected %q to be passed to OnPaste, got %q", "quit\nnow", pasted)
+	}
+}
+
+func TestPollPaste(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+
+	simulationScreen.PostEventWait(tcell.NewEventKey(tcell.KeyRune, 'a', tcell.ModNone))
+	simulationScreen.PostEventWait(tcell.NewEventResize(30, 10))
+	simulationScreen.PostEventWait(tcell.NewEventMouse(2, 3, tcell.ButtonNone, tcell.ModNone))
+	simulationScreen.PostEventWait(tcell.NewEventKey(tcell.KeyRune, 'b', tcell.ModNone))
+	simulationScreen.PostEventWait(tcell.NewEventPaste(false))
+
+	if ev := pollPaste(); ev.Type != eventPaste || ev.Text != "ab" {
+		t.Errorf("expected the paste of %q, got %+v", "ab", ev)
+	}
+	// the events received during the paste are delivered after it
+	if ev := pollEvent(); ev.Type != eventResize || ev.Width != 30 {
+		t.Errorf("expected the resize, got %+v", ev)
+	}
+	if ev := pollEvent(); ev.Type != eventMouse || ev.MouseX != 2 || ev.MouseY != 3 {
+		t.Errorf("expected the mouse event, got %+v", ev)
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+// killRingSize is the number of killed texts kept by a ReadlineEditor.
+const killRingSize = 60
+
+// readlineCommand is the kind of the last command of a ReadlineEditor.
+type readlineCommand int
+
+const (
+	readlineOther readlineCommand = iota
+	readlineKill
+	readlineYank
+)
+
+// ReadlineEditor is an Editor with the Emacs bindings of readline:
+//
+//	Ctrl+A, Ctrl+E   move to the start and to the end of the line
+//	Ctrl+B, Ctrl+F   move one character backward and forward
+//	Alt+B, Alt+F     move one word backward and forward
+//	Ctrl+D           delete the character at the cursor
+//	Ctrl+K, Ctrl+U   kill the text to the end and to the start of the line
+//	Ctrl+W           kill the word before the cursor
+//	Alt+Backspace    kill the word before the cursor
+//	Alt+D            kill the word after the cursor
+//	Ctrl+Y           yank the last killed text
+//	Alt+Y            replace the yanked text by the text killed before it
+//	Ctrl+T, Alt+T    transpose the characters and the words at the cursor
+//
+// The words are separated by spaces. The texts killed by consecutive kills
+// are yanked together. The other keys are sent to Editor. With several
+// cursors (see View.Cursors), the moves and Ctrl+D apply to every cursor,
+// and the other commands remove the cursors but the main one.
+//
+// An editor can be shared by several views, which then share its kill
+// ring. DefaultEditor edits each single line view with a ReadlineEditor of
+// its own.
+type ReadlineEditor struct {
+	// Editor edits the text for the other keys, DefaultEditor is used if
+	// it is nil.
+	Editor Editor
+
+	// kills are the killed texts, the most recent last
+	kills []string
+
+	// last is the last command, run in view
+	last readlineCommand
+	view *View
+
+	// yankX, yankY is the start of the text yanked by the last command, and
+	// yanked the index of its kill from the most recent
+	yankX, yankY, yanked int
+}
+
+// NewReadlineEditor returns an editor with an empty kill ring.
+func NewReadlineEditor() *ReadlineEditor {
+	return &ReadlineEditor{}
+}
+
+// Edit runs the readline command of the key, or sends it to the editor.
+func (e *ReadlineEditor) Edit(v *View, key Key, ch rune, mod Modifier) {
+	if e.edit(v, key, ch, mod) {
+		return
+	}
+	if isDefaultEditor(e.editor()) {
+		// the readline commands of DefaultEditor are the ones of e
+		editText(v, key, ch, mod)
+		return
+	}
+	e.editor().Edit(v, key, ch, mod)
+}
+
+// Paste sends the text to the editor.
+func (e *ReadlineEditor) Paste(v *View, text string) {
+	e.last = readlineOther
+	pasteText(e.editor(), v, text)
+}
+
+// submit tells the editor that v is submitted.
+func (e *ReadlineEditor) submit(v *View) {
+	submitEditor(e.editor(), v)
+}
+
+// editor returns the editor of the other keys.
+func (e *ReadlineEditor) editor() Editor {
+	if e.Editor == nil {
+		return DefaultEditor
+	}
+	return e.Editor
+}
+
+// edit runs the readline command of a key, and reports whether there is
+// one.
+func (e *ReadlineEditor) edit(v *View, key Key, ch rune, mod Modifier) bool {
+	last := e.last
+	if v != e.view {
+		last = readlineOther
+	}
+	e.last, e.view = readlineOther, v
+
+	line, x := e.line(v)
+	alt := func(r rune) bool {
+		return mod == ModAlt && ch == r
+	}
+	// the moves and Ctrl+D apply to every cursor, the other commands only
+	// to the main one
+	switch {
+	case key == KeyCtrlA, key == KeyHome && mod == ModNone:
+		v.moveEachCursor(func() {
+			_, x := e.line(v)
+			v.MoveCursor(-x, 0)
+		})
+	case key == KeyCtrlE, key == KeyEnd && mod == ModNone:
+		v.moveEachCursor(func() {
+			line, x := e.line(v)
+			v.MoveCursor(len(line)-x, 0)
+		})
+	case key == KeyCtrlB:
+		v.moveCursors(-1, 0)
+	case key == KeyCtrlF:
+		v.moveCursors(1, 0)
+	case alt('b'):
+		v.moveEachCursor(func() {
+			line, x := e.line(v)
+			if x == 0 {
+				// move to the end of the previous line
+				v.MoveCursor(-1, 0)
+				line, x = e.line(v)
+			}
+			v.MoveCursor(prevWordStart(line, x)-x, 0)
+		})
+	case alt('f'):
+		v.moveEachCursor(func() {
+			line, x := e.line(v)
+			if x == len(line) {
+				// move to the start of the next line
+				v.MoveCursor(1, 0)
+				line, x = e.line(v)
+			}
+			v.MoveCursor(nextWordEnd(line, x)-x, 0)
+		})
+	case key == KeyCtrlD:
+		v.EditDelete(false)
+	case key == KeyCtrlK:
+		v.ClearCursors()
+		if x == len(line) && !v.SingleLine && v.cy+1 < len(v.lines) {
+			// the newline is killed
+			v.EditDelete(false)
+			e.push(last, "\n", true)
+			break
+		}
+		e.kill(v, last, x, len(line), true)
+	case key == KeyCtrlU:
+		e.kill(v, last, 0, x, false)
+	case key == KeyCtrlW, mod == ModAlt && (key == KeyBackspace || key == KeyBackspace2):
+		e.kill(v, last, prevWordStart(line, x), x, false)
+	case alt('d'):
+		e.kill(v, last, x, nextWordEnd(line, x), true)
+	case key == KeyCtrlY:
+		e.yank(v, 0)
+	case alt('y'):
+		if last == readlineYank && len(e.kills) > 0 {
+			// the yanked text is replaced
+			for v.cy > e.yankY || v.cy == e.yankY && v.cx > e.yankX {
+				v.EditDelete(true)
+			}
+			e.yank(v, (e.yanked+1)%len(e.kills))
+		}
+	case key == KeyCtrlT:
+		v.ClearCursors()
+		if x == 0 || len(line) < 2 {
+			break
+		}
+		if x == len(line) {
+			// the last two characters are transposed
+			x--
+		}
+		line[x-1], line[x] = line[x], line[x-1]
+		v.tainted = true
+		v.MoveCursor(x+1-v.cx, 0)
+	case alt('t'):
+		e.transposeWords(v, line, x)
+	default:
+		return false
+	}
+	return true
+}
+
+// line returns the line of the cursor of v, and the cursor in it.
+func (e *ReadlineEditor) line(v *View) (line []cell, x int) {
+	if v.cy < len(v.lines) {
+		line = v.lines[v.cy]
+	}
+	x = v.cx
+	if x > len(line) {
+		x = len(line)
+	}
+	return line, x
+}
+
+// kill kills the cells from start to end of the line of the cursor, and
+// moves the cursor to start. The other cursors are removed.
+func (e *ReadlineEditor) kill(v *View, last readlineCommand, start, end int, forward bool) {
+	v.ClearCursors()
+	if start >= end {
+		return
+	}
+	line := v.lines[v.cy]
+	text := lineType(line[start:end]).String()
+	v.lines[v.cy] = append(line[:start:start], line[end:]...)
+	v.tainted = true
+	v.MoveCursor(start-v.cx, 0)
+	e.push(last, text, forward)
+}
+
+// push adds a killed text to the kill ring. The text killed after another
+// kill is added to its text, at its end if forward is true.
+func (e *ReadlineEditor) push(last readlineCommand, text string, forward bool) {
+	e.last = readlineKill
+	if n := len(e.kills); last == readlineKill && n > 0 {
+		if forward {
+			e.kills[n-1] += text
+		} else {
+			e.kills[n-1] = text + e.kills[n-1]
+		}
+		return
+	}
+	e.kills = append(e.kills, text)
+	if len(e.kills) > killRingSize {
+		e.kills = e.kills[1:]
+	}
+}
+
+// yank inserts the i-th killed text from the most recent at the cursor. The
+// other cursors are removed.
+func (e *ReadlineEditor) yank(v *View, i int) {
+	v.ClearCursors()
+	if len(e.kills) == 0 {
+		return
+	}
+	e.last = readlineYank
+	e.yankX, e.yankY, e.yanked = v.cx, v.cy, i
+	for _, r := range e.kills[len(e.kills)-1-i] {
+		if r == '\n' && !v.SingleLine {
+			v.EditNewLine()
+			continue
+		}
+		v.EditWrite(r)
+	}
+}
+
+// transposeWords moves the word before the cursor after the word at the
+// cursor, or transposes the last two words at the end of the line.
+func (e *ReadlineEditor) transposeWords(v *View, line []cell, x int) {
+	v.ClearCursors()
+	end2 := nextWordEnd(line, x)
+	start2 := prevWordStart(line, end2)
+	start1 := prevWordStart(line, start2)
+	end1 := nextWordEnd(line, start1)
+	if start1 == start2 || start2 < end1 {
+		return
+	}
+
+	newLine := make([]cell, 0, len(line))
+	newLine = append(newLine, line[:start1]...)
+	newLine = append(newLine, line[start2:end2]...)
+	newLine = append(newLine, line[end1:start2]...)
+	newLine = append(newLine, line[start1:end1]...)
+	v.lines[v.cy] = append(newLine, line[end2:]...)
+	v.tainted = true
+	v.MoveCursor(end2-v.cx, 0)
+}
+
+// nextWordEnd returns the end of the word after the cell x of line, the
+// words being separated as with indexFunc.
+func nextWordEnd(line []cell, x int) int {
+	for x < len(line) && indexFunc(line[x].chr) {
+		x++
+	}
+	for x < len(line) && !indexFunc(line[x].chr) {
+		x++
+	}
+	return x
+}
+
+// prevWordStart returns the start of the word before the cell x of line.
+func prevWordStart(line []cell, x int) int {
+	for x > 0 && indexFunc(line[x-1].chr) {
+		x--
+	}
+	for x > 0 && !indexFunc(line[x-1].chr) {
+		x--
+	}
+	return x
+}
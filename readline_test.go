@@ -0,0 +1,143 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"testing"
+)
+
+func TestReadlineEditor(t *testing.T) {
+	v := (&Gui{}).newView("v", 0, 0, 40, 2, OutputNormal)
+	e := NewReadlineEditor()
+	v.setText("foo bar baz")
+
+	// consecutive kills are yanked together
+	e.Edit(v, KeyCtrlW, 0, ModNone)
+	e.Edit(v, KeyCtrlW, 0, ModNone)
+	if got := v.Buffer(); got != "foo " {
+		t.Errorf("expected %q, got %q", "foo ", got)
+	}
+	e.Edit(v, KeyCtrlA, 0, ModNone)
+	e.Edit(v, KeyCtrlY, 0, ModNone)
+	if got := v.Buffer(); got != "bar bazfoo " {
+		t.Errorf("expected %q, got %q", "bar bazfoo ", got)
+	}
+
+	v.setText("one two")
+	e.Edit(v, 0, 'b', ModAlt)
+	e.Edit(v, KeyCtrlB, 0, ModNone)
+	e.Edit(v, KeyCtrlK, 0, ModNone)
+	e.Edit(v, KeyCtrlA, 0, ModNone)
+	e.Edit(v, 0, 'd', ModAlt)
+	if got := v.Buffer(); got != "" {
+		t.Errorf("expected the text to be killed, got %q", got)
+	}
+	// yank-pop replaces the yanked text by the previous kill
+	e.Edit(v, KeyCtrlY, 0, ModNone)
+	if got := v.Buffer(); got != "one" {
+		t.Errorf("expected %q, got %q", "one", got)
+	}
+	e.Edit(v, 0, 'y', ModAlt)
+	if got := v.Buffer(); got != " two" {
+		t.Errorf("expected %q, got %q", " two", got)
+	}
+	e.Edit(v, 0, 'y', ModAlt)
+	if got := v.Buffer(); got != "bar baz" {
+		t.Errorf("expected %q, got %q", "bar baz", got)
+	}
+
+	v.setText("foo bar")
+	e.Edit(v, 0, 't', ModAlt)
+	if got := v.Buffer(); got != "bar foo" {
+		t.Errorf("expected the words to be transposed, got %q", got)
+	}
+	v.setText("abc")
+	e.Edit(v, KeyCtrlT, 0, ModNone)
+	if got := v.Buffer(); got != "acb" {
+		t.Errorf("expected the last characters to be transposed, got %q", got)
+	}
+	e.Edit(v, KeyCtrlA, 0, ModNone)
+	e.Edit(v, KeyCtrlF, 0, ModNone)
+	e.Edit(v, KeyCtrlT, 0, ModNone)
+	if got := v.Buffer(); got != "cab" {
+		t.Errorf("expected the characters around the cursor to be transposed, got %q", got)
+	}
+	if x, _ := v.Cursor(); x != 2 {
+		t.Errorf("expected the cursor after them, got %d", x)
+	}
+}
+
+func TestReadlineEditorLines(t *testing.T) {
+	v := (&Gui{}).newView("v", 0, 0, 40, 4, OutputNormal)
+	e := NewReadlineEditor()
+	v.lines = [][]cell{textCells("one two", ColorDefault, ColorDefault), textCells("three", ColorDefault, ColorDefault)}
+	v.cx = 3
+
+	// the newline is killed at the end of a line
+	e.Edit(v, KeyCtrlK, 0, ModNone)
+	e.Edit(v, KeyCtrlK, 0, ModNone)
+	if got := v.Buffer(); got != "onethree" {
+		t.Errorf("expected %q, got %q", "onethree", got)
+	}
+	e.Edit(v, KeyCtrlY, 0, ModNone)
+	if got := v.Buffer(); got != "one two\nthree" {
+		t.Errorf("expected the text to be restored, got %q", got)
+	}
+
+	// the word motions move across lines
+	e.Edit(v, 0, 'f', ModAlt)
+	if x, y := v.Cursor(); x != 5 || y != 1 {
+		t.Errorf("expected the cursor at 5,1, got %d,%d", x, y)
+	}
+}
+
+func TestDefaultEditorSingleLine(t *testing.T) {
+	v := (&Gui{}).newView("v", 0, 0, 40, 2, OutputNormal)
+	v.SingleLine = true
+	for _, r := range "abc" {
+		v.Editor.Edit(v, 0, r, ModNone)
+	}
+	v.Editor.Edit(v, KeyCtrlA, 0, ModNone)
+	v.Editor.Edit(v, 0, 'x', ModNone)
+	if got := v.Buffer(); got != "xabc" {
+		t.Errorf("expected %q, got %q", "xabc", got)
+	}
+	v.Editor.Edit(v, KeyCtrlE, 0, ModNone)
+	v.Editor.Edit(v, KeyCtrlW, 0, ModNone)
+	if got := v.Buffer(); got != "" {
+		t.Errorf("expected the word to be killed, got %q", got)
+	}
+}
+
+func TestDefaultEditorKillRings(t *testing.T) {
+	a := (&Gui{}).newView("a", 0, 0, 40, 2, OutputNormal)
+	b := (&Gui{}).newView("b", 0, 3, 40, 5, OutputNormal)
+	a.SingleLine, b.SingleLine = true, true
+	a.setText("foo")
+	b.setText("bar")
+
+	// each view has its own kill ring
+	DefaultEditor.Edit(a, KeyCtrlU, 0, ModNone)
+	DefaultEditor.Edit(b, KeyCtrlY, 0, ModNone)
+	if got := b.Buffer(); got != "bar" {
+		t.Errorf("expected nothing to be yanked, got %q", got)
+	}
+	DefaultEditor.Edit(a, KeyCtrlY, 0, ModNone)
+	if got := a.Buffer(); got != "foo" {
+		t.Errorf("expected %q to be yanked, got %q", "foo", got)
+	}
+
+	// the keys sent by a ReadlineEditor to DefaultEditor are not looked up
+	// in the ReadlineEditor of the view
+	c := (&Gui{}).newView("c", 0, 6, 40, 8, OutputNormal)
+	c.SingleLine = true
+	NewReadlineEditor().Edit(c, 0, 'x', ModNone)
+	if got := c.Buffer(); got != "x" {
+		t.Errorf("expected %q, got %q", "x", got)
+	}
+	if c.readline != nil {
+		t.Error("expected the view not to have a ReadlineEditor")
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// styleEffects maps the names of the text effects to their attribute. The
+// order is the one used by FormatStyle.
+var styleEffects = []struct {
+	name string
+	attr Attribute
+}{
+	{"bold", AttrBold},
+	{"dim", AttrDim},
+	{"italic", AttrItalic},
+	{"underline", AttrUnderline},
+	{"blink", AttrBlink},
+	{"reverse", AttrReverse},
+	{"strikethrough", AttrStrikeThrough},
+}
+
+// styleColors are the names of the 8 basic colors, indexed by palette.
+var styleColors = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// ParseStyle parses a style specification into a foreground and a
+// background attribute. A specification is a list of words: text effects
+// (bold, dim, italic, underline, blink, reverse, strikethrough), an
+// optional foreground color, and an optional background color introduced by
+// "on", e.g.:
+//
+//	bold red on #202020
+//	underline color208
+//	italic on blue
+//
+// Colors are one of the 8 basic color names, "default", "colorN" for the
+// N-th palette color, "#rrggbb", or any W3C color name known to GetColor.
+// Text effects are always set on the foreground attribute.
+func ParseStyle(s string) (fg, bg Attribute, err error) {
+	fg, bg = ColorDefault, ColorDefault
+	var effects Attribute
+	onBg := false
+	fgSet, bgSet := false, false
+
+	for _, word := range strings.Fields(strings.ToLower(s)) {
+		if word == "on" {
+			if onBg {
+				return ColorDefault, ColorDefault, fmt.Errorf("invalid style %q: \"on\" used twice", s)
+			}
+			onBg = true
+			continue
+		}
+		if a, ok := parseStyleEffect(word); ok {
+			effects |= a
+			continue
+		}
+
+		c, err := parseStyleColor(word)
+		if err != nil {
+			return ColorDefault, ColorDefault, fmt.Errorf("invalid style %q: %w", s, err)
+		}
+		if onBg {
+			if bgSet {
+				return ColorDefault, ColorDefault, fmt.Errorf("invalid style %q: more than one background color", s)
+			}
+			bg, bgSet = c, true
+		} else {
+			if fgSet {
+				return ColorDefault, ColorDefault, fmt.Errorf("invalid style %q: more than one foreground color", s)
+			}
+			fg, fgSet = c, true
+		}
+	}
+	if onBg && !bgSet {
+		return ColorDefault, ColorDefault, fmt.Errorf("invalid style %q: missing background color", s)
+	}
+
+	return fg | effects, bg, nil
+}
+
+// MustParseStyle is like ParseStyle but panics if the style is invalid.
+func MustParseStyle(s string) (fg, bg Attribute) {
+	fg, bg, err := ParseStyle(s)
+	if err != nil {
+		panic(err)
+	}
+	return fg, bg
+}
+
+// FormatStyle returns the style specification of the given attributes. It
+// is the inverse of ParseStyle: ParseStyle(FormatStyle(fg, bg)) returns fg
+// and bg, except that text effects of bg are moved to fg.
+func FormatStyle(fg, bg Attribute) string {
+	var words []string
+	effects := (fg | bg) & AttrStyleBits
+	for _, e := range styleEffects {
+		if effects&e.attr != 0 {
+			words = append(words, e.name)
+		}
+	}
+	if c := fg & AttrColorBits; c != ColorDefault || len(words) == 0 {
+		words = append(words, formatStyleColor(c))
+	}
+	if c := bg & AttrColorBits; c != ColorDefault {
+		words = append(words, "on", formatStyleColor(c))
+	}
+	return strings.Join(words, " ")
+}
+
+// parseStyleEffect returns the attribute of a text effect name.
+func parseStyleEffect(word string) (Attribute, bool) {
+	switch word {
+	case "strike":
+		return AttrStrikeThrough, true
+	case "underlined":
+		return AttrUnderline, true
+	case "none":
+		return AttrNone, true
+	}
+	for _, e := range styleEffects {
+		if e.name == word {
+			return e.attr, true
+		}
+	}
+	return 0, false
+}
+
+// parseStyleColor converts a single color word into an Attribute.
+func parseStyleColor(word string) (Attribute, error) {
+	if word == "default" {
+		return ColorDefault, nil
+	}
+	for i, name := range styleColors {
+		if word == name {
+			return ColorBlack + Attribute(i), nil
+		}
+	}
+	if strings.HasPrefix(word, "color") {
+		n, err := strconv.Atoi(word[len("color"):])
+		if err != nil || n < 0 || n > 255 {
+			return ColorDefault, fmt.Errorf("invalid palette color %q", word)
+		}
+		return Get256Color(int32(n)), nil
+	}
+	if c := GetColor(word); c != ColorDefault {
+		return c, nil
+	}
+	return ColorDefault, fmt.Errorf("unknown color %q", word)
+}
+
+// formatStyleColor returns the name of a color, as accepted by
+// parseStyleColor.
+func formatStyleColor(c Attribute) string {
+	switch {
+	case c == ColorDefault || !c.IsValidColor():
+		return "default"
+	case c&AttrIsRGBColor != 0:
+		return fmt.Sprintf("#%06x", c.Hex())
+	}
+	n := int(c & 0xff)
+	if n < len(styleColors) {
+		return styleColors[n]
+	}
+	return "color" + strconv.Itoa(n)
+}
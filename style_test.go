@@ -0,0 +1,54 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import "testing"
+
+func TestParseStyle(t *testing.T) {
+	tests := []struct {
+		spec   string
+		fg, bg Attribute
+	}{
+		{"", ColorDefault, ColorDefault},
+		{"red", ColorRed, ColorDefault},
+		{"bold red on #202020", ColorRed | AttrBold, NewRGBColor(0x20, 0x20, 0x20)},
+		{"Underline Italic color208", Get256Color(208) | AttrUnderline | AttrItalic, ColorDefault},
+		{"on blue", ColorDefault, ColorBlue},
+		{"reverse", AttrReverse, ColorDefault},
+		{"default on default", ColorDefault, ColorDefault},
+	}
+
+	for _, tt := range tests {
+		fg, bg, err := ParseStyle(tt.spec)
+		if err != nil {
+			t.Errorf("ParseStyle(%q) returned error: %v", tt.spec, err)
+			continue
+		}
+		if fg != tt.fg || bg != tt.bg {
+			t.Errorf("ParseStyle(%q) = %x, %x, want %x, %x", tt.spec, fg, bg, tt.fg, tt.bg)
+		}
+
+		spec := FormatStyle(fg, bg)
+		fg2, bg2, err := ParseStyle(spec)
+		if err != nil || fg2 != fg || bg2 != bg {
+			t.Errorf("round trip of %q through %q failed: %x, %x, %v", tt.spec, spec, fg2, bg2, err)
+		}
+	}
+
+	for _, spec := range []string{"red blue", "on", "red on", "bold on red on blue", "nocolor", "color256"} {
+		if _, _, err := ParseStyle(spec); err == nil {
+			t.Errorf("ParseStyle(%q) should fail", spec)
+		}
+	}
+}
+
+func TestFormatStyle(t *testing.T) {
+	if got := FormatStyle(ColorGreen|AttrBold, ColorBlack|AttrUnderline); got != "bold underline green on black" {
+		t.Errorf("unexpected style %q", got)
+	}
+	if got := FormatStyle(ColorDefault, ColorDefault); got != "default" {
+		t.Errorf("unexpected style %q", got)
+	}
+}
@@ -0,0 +1,692 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// WidthPolicy is the way the width of a table column is computed.
+type WidthPolicy int
+
+// Width policies of the table columns.
+const (
+	// WidthAuto fits the column to its title and its cells, within
+	// MinWidth and MaxWidth.
+	WidthAuto WidthPolicy = iota
+
+	// WidthFixed makes the column Width columns wide.
+	WidthFixed
+
+	// WidthFill shares the room left by the other columns between the fill
+	// columns, in proportion to their Width, at least MinWidth each.
+	WidthFill
+)
+
+// Sort indicators drawn after the title of the sorted column.
+const (
+	sortAscending  = '▲'
+	sortDescending = '▼'
+)
+
+// TableColumn is a column of a Table.
+type TableColumn struct {
+	// Title is the text of the column in the header row.
+	Title string
+
+	// Policy is the way the width of the column is computed. Width is the
+	// width of a fixed column, or the weight of a fill column (1 if 0).
+	// MinWidth and MaxWidth limit the width of the other columns, a
+	// MaxWidth of 0 meaning no limit.
+	Policy                    WidthPolicy
+	Width, MinWidth, MaxWidth int
+
+	// Align is the alignment of the title and of the cells in the column.
+	Align Alignment
+
+	// If Sortable is true, the rows are sorted by the column when its
+	// title is clicked.
+	Sortable bool
+
+	// Less, if not nil, compares the cells of the column when the rows are
+	// sorted. By default the texts are compared, as numbers if both are.
+	Less func(a, b TableCell) bool
+}
+
+// TableCell is a cell of a Table. The colors are the colors of the text, the
+// default ones if they are ColorDefault.
+type TableCell struct {
+	Text             string
+	FgColor, BgColor Attribute
+}
+
+// TableRow returns a row of cells with the given texts and the default
+// colors.
+func TableRow(texts ...string) []TableCell {
+	row := make([]TableCell, len(texts))
+	for i, text := range texts {
+		row[i] = TableCell{Text: text, FgColor: ColorDefault, BgColor: ColorDefault}
+	}
+	return row
+}
+
+// Table is a Manager showing rows of cells in columns, below a header row
+// which stays in place when the rows are scrolled. A row, or a cell if
+// CellSelect is true, is selected with the arrow keys, Page Up, Page Down,
+// Home, End and the mouse, and Enter submits the selection. The columns
+// which don't fit are scrolled horizontally, except the Frozen first ones.
+// A click on the title of a sortable column sorts the rows by it, a second
+// one reverses the order.
+//
+// The view is created by Layout with SetView, with the name of the table.
+// Its content must not be written, but it can be styled like any view.
+type Table struct {
+	name           string
+	x0, y0, x1, y1 int
+	columns        []TableColumn
+	rows           [][]TableCell
+
+	// order are the indexes of the rows, in the order they are shown
+	order []int
+
+	// sortColumn is the column the rows are sorted by, or -1
+	sortColumn int
+	sortDesc   bool
+
+	// row is the position in order of the selected row, and col is the
+	// selected column
+	row, col int
+
+	// rowOffset is the first row drawn, and colOffset the first column
+	// drawn after the frozen ones
+	rowOffset, colOffset int
+
+	// widths are the widths of the columns and items the columns drawn, as
+	// last drawn
+	widths []int
+	items  []tableItem
+
+	// notifiedRow and notifiedCol are the cell selected when OnChange was
+	// last called
+	notifiedRow, notifiedCol int
+
+	// Frozen is the number of first columns which are not scrolled
+	// horizontally.
+	Frozen int
+
+	// If CellSelect is true, a cell is selected instead of a row, and the
+	// left and right arrows move the selection instead of scrolling.
+	CellSelect bool
+
+	// Separator is drawn between the columns.
+	Separator rune
+
+	// HeaderFgColor and HeaderBgColor are the colors of the header row.
+	HeaderFgColor, HeaderBgColor Attribute
+
+	// SelFgColor and SelBgColor are the colors of the selected row or cell.
+	SelFgColor, SelBgColor Attribute
+
+	// OnChange, if not nil, is called by Layout when the selection changed.
+	OnChange func(g *Gui, t *Table) error
+
+	// OnSubmit, if not nil, is called when Enter is pressed.
+	OnSubmit func(g *Gui, t *Table) error
+}
+
+// tableItem is a column drawn in the table.
+type tableItem struct {
+	// col is the index of the column
+	col int
+
+	// x is the column of the view where it is drawn, and w its width,
+	// less than the width of the column if it is truncated
+	x, w int
+}
+
+// NewTable returns a table with the given columns and no rows, covering the
+// region from (x0, y0) to (x1, y1).
+func NewTable(name string, x0, y0, x1, y1 int, columns ...TableColumn) *Table {
+	return &Table{
+		name:          name,
+		x0:            x0,
+		y0:            y0,
+		x1:            x1,
+		y1:            y1,
+		columns:       columns,
+		sortColumn:    -1,
+		notifiedRow:   -1,
+		Separator:     '│',
+		HeaderFgColor: ColorDefault | AttrBold,
+		HeaderBgColor: ColorDefault,
+		SelFgColor:    ColorDefault | AttrReverse,
+		SelBgColor:    ColorDefault,
+	}
+}
+
+// SetDimensions moves the table to the region from (x0, y0) to (x1, y1).
+func (t *Table) SetDimensions(x0, y0, x1, y1 int) {
+	t.x0, t.y0, t.x1, t.y1 = x0, y0, x1, y1
+}
+
+// Columns returns the columns of the table.
+func (t *Table) Columns() []TableColumn {
+	return t.columns
+}
+
+// Rows returns the rows of the table, in the order they were added.
+func (t *Table) Rows() [][]TableCell {
+	return t.rows
+}
+
+// SetRows replaces the rows of the table. They are sorted if a sort column
+// is set, and the first one is selected.
+func (t *Table) SetRows(rows [][]TableCell) {
+	t.rows = rows
+	t.order, t.row = nil, 0
+	t.sort()
+}
+
+// AddRow adds a row after the existing ones, or at its place if a sort
+// column is set.
+func (t *Table) AddRow(cells ...TableCell) {
+	t.rows = append(t.rows, cells)
+	t.sort()
+}
+
+// Selected returns the index of the selected row, in the order the rows
+// were added, and the selected column. The column is only highlighted if
+// CellSelect is true. It returns false if the table has no rows.
+func (t *Table) Selected() (row, col int, ok bool) {
+	if t.row >= len(t.order) {
+		return -1, t.col, false
+	}
+	return t.order[t.row], t.col, true
+}
+
+// SetSelected selects the cell of the given row and column.
+func (t *Table) SetSelected(row, col int) error {
+	if col < 0 || col >= len(t.columns) {
+		return errors.New("invalid column")
+	}
+	for i, r := range t.order {
+		if r == row {
+			t.row, t.col = i, col
+			return nil
+		}
+	}
+	return errors.New("invalid row")
+}
+
+// Sort returns the column the rows are sorted by, -1 if they are not, and
+// whether the order is descending.
+func (t *Table) Sort() (col int, desc bool) {
+	return t.sortColumn, t.sortDesc
+}
+
+// SortBy sorts the rows by the given column, in descending order if desc is
+// true. A column of -1 restores the order the rows were added in. The
+// selected row stays selected.
+func (t *Table) SortBy(col int, desc bool) error {
+	if col < -1 || col >= len(t.columns) {
+		return errors.New("invalid column")
+	}
+	t.sortColumn, t.sortDesc = col, desc
+	t.sort()
+	return nil
+}
+
+// sort computes the order of the rows.
+func (t *Table) sort() {
+	selected, _, ok := t.Selected()
+
+	t.order = t.order[:0]
+	for i := range t.rows {
+		t.order = append(t.order, i)
+	}
+	if t.sortColumn >= 0 {
+		col := t.sortColumn
+		less := t.columns[col].Less
+		if less == nil {
+			less = lessTableCells
+		}
+		sort.SliceStable(t.order, func(i, j int) bool {
+			a, b := t.cell(t.order[i], col), t.cell(t.order[j], col)
+			if t.sortDesc {
+				return less(b, a)
+			}
+			return less(a, b)
+		})
+	}
+
+	t.row = 0
+	for i, r := range t.order {
+		if ok && r == selected {
+			t.row = i
+		}
+	}
+}
+
+// lessTableCells compares the texts of two cells, as numbers if both are.
+func lessTableCells(a, b TableCell) bool {
+	x, errA := strconv.ParseFloat(strings.TrimSpace(a.Text), 64)
+	y, errB := strconv.ParseFloat(strings.TrimSpace(b.Text), 64)
+	if errA == nil && errB == nil {
+		return x < y
+	}
+	return a.Text < b.Text
+}
+
+// cell returns the cell of the given row and column, or an empty cell if
+// the row is short.
+func (t *Table) cell(row, col int) TableCell {
+	if col >= len(t.rows[row]) {
+		return TableCell{FgColor: ColorDefault, BgColor: ColorDefault}
+	}
+	return t.rows[row][col]
+}
+
+// frozen returns the number of frozen columns.
+func (t *Table) frozen() int {
+	if t.Frozen > len(t.columns) {
+		return len(t.columns)
+	}
+	return t.Frozen
+}
+
+// title returns the text of the header of the given column.
+func (t *Table) title(col int) string {
+	if col != t.sortColumn {
+		return t.columns[col].Title
+	}
+	if t.sortDesc {
+		return t.columns[col].Title + " " + string(sortDescending)
+	}
+	return t.columns[col].Title + " " + string(sortAscending)
+}
+
+// columnWidths returns the widths of the columns in a view of the given
+// width.
+func (t *Table) columnWidths(width int) []int {
+	widths := make([]int, len(t.columns))
+	room, weights := width-len(t.columns)+1, 0 // separators
+	for i, c := range t.columns {
+		switch c.Policy {
+		case WidthFixed:
+			widths[i] = c.Width
+		case WidthFill:
+			weights += fillWeight(c)
+			continue
+		default:
+			w := runewidth.StringWidth(c.Title)
+			if c.Sortable {
+				w += 2 // sort indicator
+			}
+			for row := range t.rows {
+				if cw := runewidth.StringWidth(t.cell(row, i).Text); cw > w {
+					w = cw
+				}
+			}
+			if c.MaxWidth > 0 && w > c.MaxWidth {
+				w = c.MaxWidth
+			}
+			if w < c.MinWidth {
+				w = c.MinWidth
+			}
+			widths[i] = w
+		}
+		room -= widths[i]
+	}
+
+	// the fill columns share the room left, each one taking its part of
+	// what the previous ones left, so that the last one gets the rest of
+	// the divisions
+	for i, c := range t.columns {
+		if c.Policy != WidthFill {
+			continue
+		}
+		share := 0
+		if room > 0 {
+			share = room * fillWeight(c) / weights
+		}
+		room -= share
+		weights -= fillWeight(c)
+		if share < c.MinWidth {
+			share = c.MinWidth
+		}
+		widths[i] = share
+	}
+
+	for i := range widths {
+		if widths[i] < 1 {
+			widths[i] = 1
+		}
+	}
+	return widths
+}
+
+// fillWeight returns the weight of a fill column.
+func fillWeight(c TableColumn) int {
+	if c.Width < 1 {
+		return 1
+	}
+	return c.Width
+}
+
+// layoutColumns returns the columns drawn in a view of the given width: the
+// frozen ones, then the others from colOffset, the last one being truncated
+// if it doesn't fit.
+func (t *Table) layoutColumns(widths []int, width int) []tableItem {
+	var items []tableItem
+	x := 0
+	add := func(col int) bool {
+		if x >= width {
+			return false
+		}
+		w := widths[col]
+		if x+w > width {
+			w = width - x
+		}
+		items = append(items, tableItem{col, x, w})
+		x += widths[col] + 1
+		return true
+	}
+
+	frozen := t.frozen()
+	for col := 0; col < frozen; col++ {
+		add(col)
+	}
+	for col := t.colOffset; col < len(t.columns); col++ {
+		if !add(col) {
+			break
+		}
+	}
+	return items
+}
+
+// scrollToColumn scrolls the columns horizontally to show the selected
+// column entirely, if it fits.
+func (t *Table) scrollToColumn(widths []int, width int) {
+	frozen := t.frozen()
+	if t.col < frozen {
+		return
+	}
+	if t.col < t.colOffset {
+		t.colOffset = t.col
+	}
+	x := 0
+	for col := 0; col < frozen; col++ {
+		x += widths[col] + 1
+	}
+	for t.colOffset < t.col {
+		end := x - 1
+		for col := t.colOffset; col <= t.col; col++ {
+			end += widths[col] + 1
+		}
+		if end <= width {
+			break
+		}
+		t.colOffset++
+	}
+}
+
+// Layout creates the view of the table and draws it. It calls OnChange if
+// the selection changed.
+func (t *Table) Layout(g *Gui) error {
+	v, err := g.SetView(t.name, t.x0, t.y0, t.x1, t.y1, 0)
+	if err != nil {
+		if !errors.Is(err, ErrUnknownView) {
+			return err
+		}
+		if err := t.setKeybindings(g); err != nil {
+			return err
+		}
+	}
+	t.draw(v)
+
+	row, col, _ := t.Selected()
+	if !t.CellSelect {
+		col = t.notifiedCol
+	}
+	if row == t.notifiedRow && col == t.notifiedCol {
+		return nil
+	}
+	t.notifiedRow, t.notifiedCol = row, col
+	if t.OnChange != nil {
+		return t.OnChange(g, t)
+	}
+	return nil
+}
+
+// draw writes the header and the rows shown in v.
+func (t *Table) draw(v *View) {
+	width, height := v.Size()
+
+	if t.colOffset < t.frozen() {
+		t.colOffset = t.frozen()
+	}
+	if t.colOffset >= len(t.columns) && len(t.columns) > 0 {
+		t.colOffset = len(t.columns) - 1
+	}
+	t.widths = t.columnWidths(width)
+	if t.CellSelect {
+		t.scrollToColumn(t.widths, width)
+	}
+	t.items = t.layoutColumns(t.widths, width)
+
+	// the first row of the view is the header
+	rows := height - 1
+	if t.row < t.rowOffset {
+		t.rowOffset = t.row
+	}
+	if rows > 0 && t.row >= t.rowOffset+rows {
+		t.rowOffset = t.row - rows + 1
+	}
+
+	lines := [][]cell{t.drawRow(-1, width)}
+	for i := t.rowOffset; i < len(t.order) && i < t.rowOffset+rows; i++ {
+		lines = append(lines, t.drawRow(i, width))
+	}
+	v.lines = lines
+	v.tainted = true
+	v.ox, v.oy = 0, 0
+}
+
+// drawRow returns the line of the i-th row shown, or of the header if i is
+// -1.
+func (t *Table) drawRow(i, width int) []cell {
+	rowFg, rowBg := ColorDefault, ColorDefault
+	if i < 0 {
+		rowFg, rowBg = t.HeaderFgColor, t.HeaderBgColor
+	} else if i == t.row && !t.CellSelect {
+		rowFg, rowBg = t.SelFgColor, t.SelBgColor
+	}
+
+	var line []cell
+	for _, it := range t.items {
+		if len(line) > 0 {
+			line = append(line, cell{chr: t.Separator, fgColor: rowFg, bgColor: rowBg})
+		}
+
+		var text string
+		fg, bg := rowFg, rowBg
+		if i < 0 {
+			text = t.title(it.col)
+		} else {
+			c := t.cell(t.order[i], it.col)
+			text = c.Text
+			if i == t.row && t.CellSelect && it.col == t.col {
+				fg, bg = t.SelFgColor, t.SelBgColor
+			} else if i != t.row || t.CellSelect {
+				fg, bg = c.FgColor, c.BgColor
+			}
+		}
+		line = append(line, alignCells(textCells(text, fg, bg), t.widths[it.col], it.w, t.columns[it.col].Align, fg, bg)...)
+	}
+	return padCells(line, width, rowFg, rowBg)
+}
+
+// alignCells returns text aligned in a column of the given width, of which
+// only the first visible columns are kept. The text is truncated with an
+// ellipsis if it doesn't fit.
+func alignCells(text []cell, width, visible int, align Alignment, fgColor, bgColor Attribute) []cell {
+	if w := lineWidth(text); w > width {
+		if width > 1 {
+			text = append(truncateCells(text, width-1), cell{chr: ellipsis, fgColor: fgColor, bgColor: bgColor})
+		} else {
+			text = truncateCells(text, width)
+		}
+	}
+
+	var line []cell
+	switch pad := width - lineWidth(text); align {
+	case AlignRight:
+		line = padCells(nil, pad, fgColor, bgColor)
+	case AlignCenter:
+		line = padCells(nil, pad/2, fgColor, bgColor)
+	}
+	line = append(line, text...)
+	return padCells(truncateCells(line, visible), visible, fgColor, bgColor)
+}
+
+// setKeybindings sets the keybindings of the view of the table.
+func (t *Table) setKeybindings(g *Gui) error {
+	page := func(dir int) func(*Gui, *View) error {
+		return func(g *Gui, v *View) error {
+			_, height := v.Size()
+			if height < 2 {
+				height = 2
+			}
+			t.moveRow(dir * (height - 1))
+			return nil
+		}
+	}
+	step := func(n int) func(*Gui, *View) error {
+		return func(*Gui, *View) error {
+			t.moveRow(n)
+			return nil
+		}
+	}
+	side := func(n int) func(*Gui, *View) error {
+		return func(*Gui, *View) error {
+			t.moveColumn(n)
+			return nil
+		}
+	}
+
+	bindings := []struct {
+		key     Key
+		handler func(*Gui, *View) error
+	}{
+		{KeyArrowUp, step(-1)},
+		{KeyArrowDown, step(1)},
+		{MouseWheelUp, step(-1)},
+		{MouseWheelDown, step(1)},
+		{KeyPgup, page(-1)},
+		{KeyPgdn, page(1)},
+		{KeyHome, t.onHome},
+		{KeyEnd, t.onEnd},
+		{KeyArrowLeft, side(-1)},
+		{KeyArrowRight, side(1)},
+		{KeyEnter, t.onEnter},
+		{MouseLeft, t.onClick},
+	}
+	for _, b := range bindings {
+		if err := g.SetKeybinding(t.name, b.key, ModNone, b.handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveRow moves the selection by n rows.
+func (t *Table) moveRow(n int) {
+	t.row += n
+	if t.row >= len(t.order) {
+		t.row = len(t.order) - 1
+	}
+	if t.row < 0 {
+		t.row = 0
+	}
+}
+
+// moveColumn moves the selection by n columns with CellSelect, otherwise it
+// scrolls the columns horizontally, until the last one is shown entirely.
+func (t *Table) moveColumn(n int) {
+	if t.CellSelect {
+		t.col += n
+		if t.col >= len(t.columns) {
+			t.col = len(t.columns) - 1
+		}
+		if t.col < 0 {
+			t.col = 0
+		}
+		return
+	}
+
+	if n > 0 && len(t.items) > 0 {
+		last := t.items[len(t.items)-1]
+		if last.col == len(t.columns)-1 && last.w == t.widths[last.col] {
+			return
+		}
+	}
+	t.colOffset += n
+	if t.colOffset < t.frozen() {
+		t.colOffset = t.frozen()
+	}
+}
+
+// onHome selects the first row.
+func (t *Table) onHome(g *Gui, v *View) error {
+	t.row = 0
+	return nil
+}
+
+// onEnd selects the last row.
+func (t *Table) onEnd(g *Gui, v *View) error {
+	t.moveRow(len(t.order))
+	return nil
+}
+
+// onEnter submits the selection.
+func (t *Table) onEnter(g *Gui, v *View) error {
+	if t.OnSubmit == nil {
+		return nil
+	}
+	return t.OnSubmit(g, t)
+}
+
+// onClick sorts the rows by the column whose title was clicked, or selects
+// the clicked row or cell.
+func (t *Table) onClick(g *Gui, v *View) error {
+	mx, my := g.MousePosition()
+	x, y := mx-v.x0-1, my-v.y0-1
+
+	col := -1
+	for _, it := range t.items {
+		if x >= it.x && x < it.x+it.w {
+			col = it.col
+		}
+	}
+
+	if y == 0 {
+		if col < 0 || !t.columns[col].Sortable {
+			return nil
+		}
+		return t.SortBy(col, col == t.sortColumn && !t.sortDesc)
+	}
+	if row := t.rowOffset + y - 1; row < len(t.order) {
+		t.row = row
+		if t.CellSelect && col >= 0 {
+			t.col = col
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"testing"
+)
+
+func TestTableColumnWidths(t *testing.T) {
+	table := NewTable("table", 0, 0, 40, 5,
+		TableColumn{Title: "Name", MaxWidth: 6},
+		TableColumn{Title: "Size", Policy: WidthFixed, Width: 5},
+		TableColumn{Title: "A", Policy: WidthFill},
+		TableColumn{Title: "B", Policy: WidthFill, Width: 2},
+	)
+	table.SetRows([][]TableCell{TableRow("a long name", "1")})
+
+	// 30 columns, less 3 separators, 6 and 5 for the first columns: 16
+	// columns are shared by the fill columns
+	want := []int{6, 5, 5, 11}
+	got := table.columnWidths(30)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestTableSort(t *testing.T) {
+	table := NewTable("table", 0, 0, 40, 5, TableColumn{Title: "Name"}, TableColumn{Title: "Size", Sortable: true})
+	table.SetRows([][]TableCell{TableRow("a", "10"), TableRow("b", "9"), TableRow("c", "100")})
+	if err := table.SetSelected(2, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// sizes are compared as numbers
+	if err := table.SortBy(1, true); err != nil {
+		t.Fatal(err)
+	}
+	want := []int{2, 0, 1}
+	for i := range want {
+		if table.order[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, table.order)
+		}
+	}
+	// the selected row stays selected
+	if row, _, _ := table.Selected(); row != 2 || table.row != 0 {
+		t.Errorf("expected row 2 to be selected first, got %d at %d", row, table.row)
+	}
+}
+
+func TestTableFrozenColumns(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := NewTable("table", 0, 0, 12, 5,
+		TableColumn{Title: "K", Policy: WidthFixed, Width: 2},
+		TableColumn{Title: "One", Policy: WidthFixed, Width: 5},
+		TableColumn{Title: "Two", Policy: WidthFixed, Width: 5},
+	)
+	table.Frozen = 1
+	table.CellSelect = true
+	table.SetRows([][]TableCell{TableRow("k", "1", "2")})
+	g.SetManager(table)
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	done := make(chan struct{})
+	g.Update(func(g *Gui) error {
+		defer close(done)
+		_, err := g.SetCurrentView("table")
+		return err
+	})
+	<-done
+	testingScreen.WaitSync()
+
+	// selecting the last column scrolls the second one out, the first one
+	// stays
+	testingScreen.SendKeySync(KeyArrowRight)
+	testingScreen.SendKeySync(KeyArrowRight)
+	testingScreen.WaitSync()
+
+	for y, want := range []string{"K │Two  ", "k │2    "} {
+		for x, r := range []rune(want) {
+			if got, _ := g.Rune(x+1, y+1); got != r {
+				t.Errorf("row %d column %d: expected %q, got %q", y, x, r, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,469 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Tab is a tab of a Tabs container.
+type Tab struct {
+	// Name is the name of the view shown when the tab is active.
+	Name string
+
+	// Label is the text of the tab in the tab strip. Name is used if it is
+	// empty.
+	Label string
+
+	// If Closable is true, the label has a close button.
+	Closable bool
+}
+
+// label returns the text of the tab in the tab strip.
+func (t Tab) label() string {
+	if t.Label != "" {
+		return t.Label
+	}
+	return t.Name
+}
+
+// tabClose is the close button of the closable tabs.
+const tabClose = '×'
+
+// Tabs is a Manager showing a set of views which share the same region, only
+// the view of the active tab being visible. The labels of the tabs are drawn
+// in a tab strip, on the top edge of the frame of the views or in a header
+// row above them. A tab is activated with a click on its label, or with
+// NextKey and PrevKey from the view of the active tab, and closed with a
+// click on its close button. When the labels don't fit, the strip can be
+// scrolled with a click on the arrows drawn at its ends.
+//
+// The views of the tabs are created with SetView by Layout if they don't
+// exist: they can be initialized like any other view.
+type Tabs struct {
+	name           string
+	x0, y0, x1, y1 int
+	tabs           []Tab
+	active         int
+
+	// offset is the index of the first tab drawn in the strip
+	offset int
+
+	// scrollToActive is true if the strip must be scrolled to show the
+	// active tab
+	scrollToActive bool
+
+	// items are the elements of the strip, as last drawn
+	items []tabItem
+
+	// If Header is true, the tab strip is drawn in a header row above the
+	// views, instead of on the top edge of their frame.
+	Header bool
+
+	// ActiveFgColor and ActiveBgColor are the colors of the label of the
+	// active tab.
+	ActiveFgColor, ActiveBgColor Attribute
+
+	// NextKey and PrevKey, pressed with KeyMod, activate the next and the
+	// previous tab. They are Keys or runes, as for Gui.SetKeybinding, nil
+	// for no binding: Alt+PgDn and Alt+PgUp by default. They are bound to
+	// the views of the tabs created by Layout, so they must be set before.
+	NextKey, PrevKey interface{}
+	KeyMod           Modifier
+
+	// OnChange, if not nil, is called when another tab is activated.
+	OnChange func(g *Gui, t Tab) error
+
+	// OnClose, if not nil, is called when a tab is closed, after its view
+	// was deleted.
+	OnClose func(g *Gui, t Tab) error
+}
+
+// NewTabs returns a Tabs container with the given tabs, covering the region
+// from (x0, y0) to (x1, y1). name is the name of the view of the tab strip.
+func NewTabs(name string, x0, y0, x1, y1 int, tabs ...Tab) *Tabs {
+	return &Tabs{
+		name:          name,
+		x0:            x0,
+		y0:            y0,
+		x1:            x1,
+		y1:            y1,
+		tabs:          tabs,
+		ActiveFgColor: ColorDefault | AttrReverse,
+		ActiveBgColor: ColorDefault,
+		NextKey:       KeyPgdn,
+		PrevKey:       KeyPgup,
+		KeyMod:        ModAlt,
+	}
+}
+
+// SetDimensions moves the container to the region from (x0, y0) to
+// (x1, y1).
+func (t *Tabs) SetDimensions(x0, y0, x1, y1 int) {
+	t.x0, t.y0, t.x1, t.y1 = x0, y0, x1, y1
+}
+
+// Tabs returns the tabs of the container.
+func (t *Tabs) Tabs() []Tab {
+	return t.tabs
+}
+
+// Active returns the active tab. It returns false if there are no tabs.
+func (t *Tabs) Active() (Tab, bool) {
+	if len(t.tabs) == 0 {
+		return Tab{}, false
+	}
+	return t.tabs[t.active], true
+}
+
+// AddTab adds a tab after the existing ones.
+func (t *Tabs) AddTab(tab Tab) {
+	t.tabs = append(t.tabs, tab)
+}
+
+// SetActive activates the tab with the given view name. If the view of the
+// previous tab had the focus, the view of the new one gets it.
+func (t *Tabs) SetActive(g *Gui, name string) error {
+	i := t.index(name)
+	if i < 0 {
+		return ErrUnknownView
+	}
+	return t.activate(g, i)
+}
+
+// NextTab activates the next tab, or the first one after the last one. It
+// can be used as a keybinding handler.
+func (t *Tabs) NextTab(g *Gui, v *View) error {
+	if len(t.tabs) == 0 {
+		return nil
+	}
+	return t.activate(g, (t.active+1)%len(t.tabs))
+}
+
+// PrevTab activates the previous tab, or the last one before the first one.
+// It can be used as a keybinding handler.
+func (t *Tabs) PrevTab(g *Gui, v *View) error {
+	if len(t.tabs) == 0 {
+		return nil
+	}
+	return t.activate(g, (t.active+len(t.tabs)-1)%len(t.tabs))
+}
+
+// CloseTab closes the tab with the given view name and deletes its view.
+func (t *Tabs) CloseTab(g *Gui, name string) error {
+	i := t.index(name)
+	if i < 0 {
+		return ErrUnknownView
+	}
+	tab := t.tabs[i]
+	focused := g.CurrentView() != nil && g.CurrentView().Name() == tab.Name
+
+	if err := g.DeleteView(tab.Name); err != nil && !errors.Is(err, ErrUnknownView) {
+		return err
+	}
+	t.deleteKeybindings(g, tab.Name)
+	t.tabs = append(t.tabs[:i], t.tabs[i+1:]...)
+	if t.active > i || t.active == len(t.tabs) {
+		t.active--
+	}
+	if t.active < 0 {
+		t.active = 0
+	}
+	if t.offset > 0 && t.offset >= len(t.tabs) {
+		t.offset = len(t.tabs) - 1
+	}
+
+	if focused && len(t.tabs) > 0 {
+		if _, err := g.SetCurrentView(t.tabs[t.active].Name); err != nil && !errors.Is(err, ErrUnknownView) && !errors.Is(err, ErrModalActive) {
+			return err
+		}
+	}
+	if t.OnClose != nil {
+		return t.OnClose(g, tab)
+	}
+	return nil
+}
+
+// index returns the index of the tab with the given view name, or -1.
+func (t *Tabs) index(name string) int {
+	for i, tab := range t.tabs {
+		if tab.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// tabKey is a key switching tabs, and its handler.
+type tabKey struct {
+	key     interface{}
+	handler func(*Gui, *View) error
+}
+
+// keybindings returns the keys switching tabs.
+func (t *Tabs) keybindings() []tabKey {
+	return []tabKey{{t.NextKey, t.NextTab}, {t.PrevKey, t.PrevTab}}
+}
+
+// setKeybindings binds the keys switching tabs to the view with the given
+// name.
+func (t *Tabs) setKeybindings(g *Gui, name string) error {
+	for _, b := range t.keybindings() {
+		if b.key == nil {
+			continue
+		}
+		if err := g.SetKeybinding(name, b.key, t.KeyMod, b.handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteKeybindings deletes the keys switching tabs of the view with the
+// given name, leaving its other keybindings.
+func (t *Tabs) deleteKeybindings(g *Gui, name string) {
+	for _, b := range t.keybindings() {
+		if b.key != nil {
+			// the key may have been changed since it was bound
+			_ = g.DeleteKeybinding(name, b.key, t.KeyMod)
+		}
+	}
+}
+
+// activate activates the i-th tab and scrolls the strip to show it.
+func (t *Tabs) activate(g *Gui, i int) error {
+	if i == t.active {
+		return nil
+	}
+	prev := t.tabs[t.active].Name
+	t.active = i
+	if i < t.offset {
+		t.offset = i
+	}
+	t.scrollToActive = true
+
+	if cur := g.CurrentView(); cur != nil && cur.Name() == prev {
+		if v, err := g.View(t.tabs[i].Name); err == nil {
+			v.Visible = true
+			if _, err := g.SetCurrentView(v.Name()); err != nil && !errors.Is(err, ErrModalActive) {
+				return err
+			}
+		}
+	}
+	if t.OnChange != nil {
+		return t.OnChange(g, t.tabs[i])
+	}
+	return nil
+}
+
+// Layout places the views of the tabs and draws the tab strip.
+func (t *Tabs) Layout(g *Gui) error {
+	y0 := t.y0
+	if t.Header {
+		y0++
+	}
+
+	names := make([]string, len(t.tabs))
+	zIndex := 0
+	for i, tab := range t.tabs {
+		v, err := g.SetView(tab.Name, t.x0, y0, t.x1, t.y1, 0)
+		if err != nil {
+			if !errors.Is(err, ErrUnknownView) {
+				return err
+			}
+			if err := t.setKeybindings(g, tab.Name); err != nil {
+				return err
+			}
+		}
+		v.Visible = i == t.active
+		if i == 0 || v.ZIndex > zIndex {
+			zIndex = v.ZIndex
+		}
+		names[i] = tab.Name
+	}
+
+	// the strip is a one row view, on the top edge of the frame of the
+	// tabs or above them
+	v, err := g.SetView(t.name, t.x0, t.y0-1, t.x1, t.y0+1, 0)
+	if err != nil {
+		if !errors.Is(err, ErrUnknownView) {
+			return err
+		}
+		if err := g.SetKeybinding(t.name, MouseLeft, ModNone, t.onClick); err != nil {
+			return err
+		}
+	}
+	v.Frame = false
+	v.Transparent = !t.Header
+	v.ZIndex = zIndex
+	g.setViewAbove(v, names)
+
+	t.drawStrip(v)
+	return nil
+}
+
+// tabItem is an element of the tab strip: the label of a tab or a scroll
+// arrow.
+type tabItem struct {
+	// tab is the index of the tab, or tabScrollLeft or tabScrollRight
+	tab int
+
+	// x is the column of the item in the strip, and w its width
+	x, w int
+}
+
+// Items of the tab strip which are not tabs.
+const (
+	tabScrollLeft  = -1
+	tabScrollRight = -2
+)
+
+// stripStart returns the column of the strip view where the strip starts:
+// on the frame, it starts after one edge rune.
+func (t *Tabs) stripStart() int {
+	if t.Header {
+		return 0
+	}
+	return 1
+}
+
+// stripWidth returns the number of columns of the strip.
+func (t *Tabs) stripWidth() int {
+	w := t.x1 - t.x0 - 1
+	if !t.Header {
+		w -= 2 // one edge rune on both sides
+	}
+	return w
+}
+
+// tabWidth returns the number of columns of the label of the i-th tab.
+func (t *Tabs) tabWidth(i int) int {
+	w := runewidth.StringWidth(t.tabs[i].label())
+	if t.tabs[i].Closable {
+		w += 2
+	}
+	return w
+}
+
+// layoutStrip returns the items of a strip of the given width, scrolled so
+// that the active tab is visible if it was just activated.
+func (t *Tabs) layoutStrip(width int) []tabItem {
+	total := -1
+	for i := range t.tabs {
+		total += t.tabWidth(i) + 1
+	}
+	scroll := total > width
+	if !scroll {
+		t.offset = 0
+	}
+
+	// arrows take two columns on each side
+	x, limit := 0, width
+	if scroll {
+		x, limit = 2, width-2
+	}
+
+	if scroll && t.scrollToActive {
+		if t.active < t.offset {
+			t.offset = t.active
+		}
+		for t.offset < t.active {
+			w := -1
+			for i := t.offset; i <= t.active; i++ {
+				w += t.tabWidth(i) + 1
+			}
+			if x+w <= limit {
+				break
+			}
+			t.offset++
+		}
+	}
+	t.scrollToActive = false
+
+	var items []tabItem
+	if scroll && t.offset > 0 {
+		items = append(items, tabItem{tabScrollLeft, 0, 1})
+	}
+	last := t.offset - 1
+	for i := t.offset; i < len(t.tabs); i++ {
+		w := t.tabWidth(i)
+		if x+w > limit {
+			if i > t.offset {
+				break
+			}
+			w = limit - x // the only tab drawn is truncated
+		}
+		items = append(items, tabItem{i, x, w})
+		x += w + 1
+		last = i
+	}
+	if scroll && last < len(t.tabs)-1 {
+		items = append(items, tabItem{tabScrollRight, width - 1, 1})
+	}
+	return items
+}
+
+// drawStrip draws the tab strip in v.
+func (t *Tabs) drawStrip(v *View) {
+	t.items = t.layoutStrip(t.stripWidth())
+
+	var line []cell
+	start := t.stripStart()
+	for _, it := range t.items {
+		line = padCells(line, start+it.x, ColorDefault, ColorDefault)
+		fg, bg := ColorDefault, ColorDefault
+		var text string
+		switch it.tab {
+		case tabScrollLeft:
+			text = "‹"
+		case tabScrollRight:
+			text = "›"
+		default:
+			tab := t.tabs[it.tab]
+			text = tab.label()
+			if tab.Closable {
+				text += " " + string(tabClose)
+			}
+			if it.tab == t.active {
+				fg, bg = t.ActiveFgColor, t.ActiveBgColor
+			}
+		}
+		if !t.Header {
+			// non-breaking spaces are not transparent, unlike spaces
+			text = strings.Replace(text, " ", "\u00a0", -1)
+		}
+		line = append(line, truncateCells(textCells(text, fg, bg), it.w)...)
+	}
+
+	v.lines = [][]cell{line}
+	v.tainted = true
+}
+
+// onClick activates or closes the tab clicked in the strip, or scrolls it.
+func (t *Tabs) onClick(g *Gui, v *View) error {
+	mx, _ := g.MousePosition()
+	col := mx - v.x0 - 1 - t.stripStart()
+	for _, it := range t.items {
+		if col < it.x || col >= it.x+it.w {
+			continue
+		}
+		switch it.tab {
+		case tabScrollLeft:
+			t.offset--
+		case tabScrollRight:
+			t.offset++
+		default:
+			tab := t.tabs[it.tab]
+			if tab.Closable && col == it.x+it.w-1 {
+				return t.CloseTab(g, tab.Name)
+			}
+			return t.activate(g, it.tab)
+		}
+	}
+	return nil
+}
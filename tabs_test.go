@@ -0,0 +1,146 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTabsStripOverflow(t *testing.T) {
+	tabs := NewTabs("tabs", 0, 0, 20, 5,
+		Tab{Name: "one"}, Tab{Name: "two"}, Tab{Name: "three"}, Tab{Name: "four"}, Tab{Name: "five"})
+
+	want := []tabItem{{0, 2, 3}, {1, 6, 3}, {tabScrollRight, 11, 1}}
+	if got := tabs.layoutStrip(12); !equalTabItems(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// the strip is scrolled to show the active tab
+	tabs.active, tabs.scrollToActive = 3, true
+	want = []tabItem{{tabScrollLeft, 0, 1}, {3, 2, 4}, {tabScrollRight, 11, 1}}
+	if got := tabs.layoutStrip(12); !equalTabItems(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func equalTabItems(a, b []tabItem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTabsFrameStrip(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tabs := NewTabs("tabs", 0, 0, 20, 5, Tab{Name: "a", Label: "A b"}, Tab{Name: "b", Closable: true})
+	g.SetManager(tabs)
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	// the labels are drawn on the frame, which shows between them
+	want := []rune{'┌', '─', 'A', ' ', 'b', '─', 'b', ' ', '×', '─'}
+	for x, r := range want {
+		if got, _ := g.Rune(x, 0); got != r {
+			t.Errorf("column %d: expected %q, got %q", x, r, got)
+		}
+	}
+
+	done := make(chan struct{})
+	g.Update(func(g *Gui) error {
+		defer close(done)
+		v, err := g.View("tabs")
+		if err != nil {
+			return err
+		}
+		// click on the close button
+		g.mouseX, g.mouseY = 8, 0
+		return tabs.onClick(g, v)
+	})
+	<-done
+	testingScreen.WaitSync()
+
+	if len(tabs.Tabs()) != 1 {
+		t.Errorf("expected the tab to be closed, got %v", tabs.Tabs())
+	}
+	if _, err := g.View("b"); !errors.Is(err, ErrUnknownView) {
+		t.Error("expected the view of the tab to be deleted")
+	}
+}
+
+func TestTabsKeys(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tabs := NewTabs("tabs", 0, 0, 20, 5, Tab{Name: "a"}, Tab{Name: "b"}, Tab{Name: "c"})
+	tabs.PrevKey = KeyF2
+	tabs.KeyMod = ModNone
+	tabs.NextKey = KeyF3
+	g.SetManager(tabs)
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	done := make(chan struct{})
+	g.Update(func(g *Gui) error {
+		defer close(done)
+		_, err := g.SetCurrentView("a")
+		return err
+	})
+	<-done
+	testingScreen.WaitSync()
+
+	active := func() string {
+		tab, _ := tabs.Active()
+		return tab.Name
+	}
+	testingScreen.SendKeySync(KeyF3)
+	if name := active(); name != "b" {
+		t.Errorf("expected the next tab to be active, got %q", name)
+	}
+	if v := g.CurrentView(); v == nil || v.Name() != "b" {
+		t.Error("expected the view of the next tab to have the focus")
+	}
+	testingScreen.SendKeySync(KeyF2)
+	testingScreen.SendKeySync(KeyF2)
+	if name := active(); name != "c" {
+		t.Errorf("expected the last tab to be active, got %q", name)
+	}
+}
+
+func TestTabsDefaultKeys(t *testing.T) {
+	g := &Gui{}
+	tabs := NewTabs("tabs", 0, 0, 20, 5, Tab{Name: "a"})
+	if err := tabs.setKeybindings(g, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if len(g.keybindings) != 2 {
+		t.Fatalf("expected 2 keybindings, got %d", len(g.keybindings))
+	}
+	for i, key := range []Key{KeyPgdn, KeyPgup} {
+		if kb := g.keybindings[i]; kb.viewName != "a" || kb.key != key || kb.mod != ModAlt {
+			t.Errorf("unexpected keybinding %+v", kb)
+		}
+	}
+
+	// the keys are deleted with the tab, unlike the other keybindings
+	if err := g.SetKeybinding("a", KeyEnter, ModNone, nil); err != nil {
+		t.Fatal(err)
+	}
+	tabs.deleteKeybindings(g, "a")
+	if len(g.keybindings) != 1 || g.keybindings[0].key != KeyEnter {
+		t.Errorf("expected only the other keybinding to be kept, got %d", len(g.keybindings))
+	}
+}
@@ -45,16 +45,45 @@ func tcellInitSimulation() error {
 }
 
 // tcellSetCell sets the character cell at a given location to the given
-// content (rune) and attributes using provided OutputMode
-func tcellSetCell(x, y int, ch rune, fg, bg Attribute, omode OutputMode) {
+// content (rune and its combining runes) and attributes using provided OutputMode
+func tcellSetCell(x, y int, ch rune, combining []rune, fg, bg Attribute, omode OutputMode) {
 	st := getTcellStyle(fg, bg, omode)
-	screen.SetContent(x, y, ch, nil, st)
+	screen.SetContent(x, y, ch, combining, st)
+}
+
+// tcellDim dims all the cells of the screen.
+func tcellDim() {
+	w, h := screen.Size()
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			ch, combining, st, _ := screen.GetContent(x, y)
+			screen.SetContent(x, y, ch, combining, st.Dim(true))
+		}
+	}
+}
+
+// tcellShadeCell dims the cell at (x, y), and sets its background to bg
+// unless it is ColorDefault.
+func tcellShadeCell(x, y int, bg Attribute, omode OutputMode) {
+	ch, combining, st, _ := screen.GetContent(x, y)
+	st = st.Dim(true)
+	if bg != ColorDefault && omode != OutputMonochrome {
+		_, b, _ := getTcellStyle(ColorDefault, bg, omode).Decompose()
+		st = st.Background(b)
+	}
+	screen.SetContent(x, y, ch, combining, st)
 }
 
 // getTcellStyle creates tcell.Style from Attributes
 func getTcellStyle(fg, bg Attribute, omode OutputMode) tcell.Style {
 	st := tcell.StyleDefault
 
+	// monochrome drops the colors but keeps the effects
+	if omode == OutputMonochrome {
+		st = setTcellFontEffectStyle(st, fg)
+		return setTcellFontEffectStyle(st, bg)
+	}
+
 	// extract colors and attributes
 	if fg != ColorDefault {
 		st = st.Foreground(getTcellColor(fg, omode))
@@ -102,6 +131,7 @@ type gocuiEventType uint8
 //  The 'MouseX' and 'MouseY' fields are valid if 'Type' is 'eventMouse'.
 //  The 'Width' and 'Height' fields are valid if 'Type' is 'eventResize'.
 //  The 'Err' field is valid if 'Type' is 'eventError'.
+//  The 'Text' field is valid if 'Type' is 'eventPaste'.
 type gocuiEvent struct {
 	Type   gocuiEventType
 	Mod    Modifier
@@ -113,6 +143,7 @@ type gocuiEvent struct {
 	MouseX int
 	MouseY int
 	N      int
+	Text   string
 }
 
 // Event types.
@@ -125,16 +156,26 @@ const (
 	eventError
 	eventRaw
 	eventTime
+	eventPaste
 )
 
 var (
 	lastMouseKey tcell.ButtonMask = tcell.ButtonNone
 	lastMouseMod tcell.ModMask    = tcell.ModNone
+
+	// pendingEvents are the events received during a paste, delivered
+	// after it
+	pendingEvents []tcell.Event
 )
 
 // pollEvent get tcell.Event and transform it into gocuiEvent
 func pollEvent() gocuiEvent {
-	tev := screen.PollEvent()
+	var tev tcell.Event
+	if len(pendingEvents) > 0 {
+		tev, pendingEvents = pendingEvents[0], pendingEvents[1:]
+	} else {
+		tev = screen.PollEvent()
+	}
 	switch tev := tev.(type) {
 	case *tcell.EventInterrupt:
 		return gocuiEvent{Type: eventInterrupt}
@@ -230,7 +271,41 @@ func pollEvent() gocuiEvent {
 		}
 	case *tcell.EventTime:
 		return gocuiEvent{Type: eventTime}
+	case *tcell.EventPaste:
+		if tev.Start() {
+			return pollPaste()
+		}
+		return gocuiEvent{Type: eventNone}
 	default:
 		return gocuiEvent{Type: eventNone}
 	}
 }
+
+// pollPaste collects the keys of a bracketed paste until its end, and
+// returns them as a single event. The other events received meanwhile, like
+// resizes and mouse events, are kept in pendingEvents.
+func pollPaste() gocuiEvent {
+	var text []rune
+	for {
+		switch tev := screen.PollEvent().(type) {
+		case nil:
+			// the screen was finalized
+			return gocuiEvent{Type: eventPaste, Text: string(text)}
+		case *tcell.EventPaste:
+			if tev.End() {
+				return gocuiEvent{Type: eventPaste, Text: string(text)}
+			}
+		case *tcell.EventKey:
+			switch tev.Key() {
+			case tcell.KeyRune:
+				text = append(text, tev.Rune())
+			case tcell.KeyEnter, tcell.KeyLF:
+				text = append(text, '\n')
+			case tcell.KeyTab:
+				text = append(text, '\t')
+			}
+		default:
+			pendingEvents = append(pendingEvents, tev)
+		}
+	}
+}
@@ -87,6 +87,22 @@ func (t *TestingScreen) SendKeySync(key Key) {
 	t.WaitSync()
 }
 
+// SendPaste sends text to gocui as a bracketed paste.
+func (t *TestingScreen) SendPaste(text string) {
+	if !t.started {
+		panic("TestingScreen must be started using 'StartGui' before injecting keys")
+	}
+	t.screen.PostEventWait(tcell.NewEventPaste(true))
+	for _, r := range text {
+		if r == '\n' {
+			t.screen.PostEventWait(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+			continue
+		}
+		t.screen.PostEventWait(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	t.screen.PostEventWait(tcell.NewEventPaste(false))
+}
+
 // WaitSync sends time event to gocui and awaits notification that it was received.
 //
 // Notification is sent from gocui at the end of MainLoop, so after this function returns,
@@ -0,0 +1,218 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// Theme groups the colors used to draw the GUI by role. A theme is applied
+// to the Gui and to all its views with Gui.SetTheme.
+type Theme struct {
+	// Name is the name of the theme.
+	Name string
+
+	// FgColor and BgColor are the colors of the content of the views.
+	FgColor, BgColor Attribute
+
+	// FrameColor and TitleColor are the colors of the frame and of the
+	// title of the views.
+	FrameColor, TitleColor Attribute
+
+	// SelFgColor and SelBgColor are the colors of the highlighted line.
+	SelFgColor, SelBgColor Attribute
+
+	// FocusFrameColor, FocusTitleColor and FocusBgColor are the colors of
+	// the frame of the current view, when Gui.Highlight is true.
+	FocusFrameColor, FocusTitleColor, FocusBgColor Attribute
+}
+
+// DefaultTheme returns a theme which leaves every color to the terminal
+// default.
+func DefaultTheme() *Theme {
+	return &Theme{
+		Name:            "default",
+		FgColor:         ColorDefault,
+		BgColor:         ColorDefault,
+		FrameColor:      ColorDefault,
+		TitleColor:      ColorDefault,
+		SelFgColor:      ColorDefault,
+		SelBgColor:      ColorDefault,
+		FocusFrameColor: ColorDefault,
+		FocusTitleColor: ColorDefault,
+		FocusBgColor:    ColorDefault,
+	}
+}
+
+// themeFile is the serialized form of a Theme. Colors are written as in
+// ParseStyle, e.g. "bold yellow" or "#202020".
+type themeFile struct {
+	Name            string `json:"name" toml:"name" yaml:"name"`
+	Fg              string `json:"fg" toml:"fg" yaml:"fg"`
+	Bg              string `json:"bg" toml:"bg" yaml:"bg"`
+	Frame           string `json:"frame" toml:"frame" yaml:"frame"`
+	Title           string `json:"title" toml:"title" yaml:"title"`
+	SelectedFg      string `json:"selected_fg" toml:"selected_fg" yaml:"selected_fg"`
+	SelectedBg      string `json:"selected_bg" toml:"selected_bg" yaml:"selected_bg"`
+	FocusFrame      string `json:"focus_frame" toml:"focus_frame" yaml:"focus_frame"`
+	FocusTitle      string `json:"focus_title" toml:"focus_title" yaml:"focus_title"`
+	FocusBackground string `json:"focus_bg" toml:"focus_bg" yaml:"focus_bg"`
+}
+
+// themeUnmarshalers are the unmarshal functions of the theme files, by
+// extension.
+var themeUnmarshalers = map[string]func([]byte, interface{}) error{
+	".json": json.Unmarshal,
+	".toml": toml.Unmarshal,
+	".yaml": yaml.Unmarshal,
+	".yml":  yaml.Unmarshal,
+}
+
+// LoadTheme decodes a theme using the given unmarshal function, such as
+// json.Unmarshal, which makes it possible to load themes from any format.
+// The field names are name, fg, bg, frame, title, selected_fg, selected_bg,
+// focus_frame, focus_title and focus_bg. Missing colors are set to
+// ColorDefault.
+func LoadTheme(data []byte, unmarshal func([]byte, interface{}) error) (*Theme, error) {
+	var f themeFile
+	if err := unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to decode theme: %w", err)
+	}
+
+	t := &Theme{Name: f.Name}
+	colors := []struct {
+		dst  *Attribute
+		role string
+		src  string
+	}{
+		{&t.FgColor, "fg", f.Fg},
+		{&t.BgColor, "bg", f.Bg},
+		{&t.FrameColor, "frame", f.Frame},
+		{&t.TitleColor, "title", f.Title},
+		{&t.SelFgColor, "selected_fg", f.SelectedFg},
+		{&t.SelBgColor, "selected_bg", f.SelectedBg},
+		{&t.FocusFrameColor, "focus_frame", f.FocusFrame},
+		{&t.FocusTitleColor, "focus_title", f.FocusTitle},
+		{&t.FocusBgColor, "focus_bg", f.FocusBackground},
+	}
+	for _, c := range colors {
+		a, err := parseThemeColor(c.src)
+		if err != nil {
+			return nil, fmt.Errorf("invalid color for %s: %w", c.role, err)
+		}
+		*c.dst = a
+	}
+	return t, nil
+}
+
+// LoadThemeFile reads a theme from a file, whose format is chosen by its
+// extension: .json, .toml, .yaml or .yml. The themes in other formats are
+// read with LoadTheme. The name of the theme defaults to the name of the
+// file.
+func LoadThemeFile(path string) (*Theme, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	unmarshal, ok := themeUnmarshalers[ext]
+	if !ok {
+		return nil, fmt.Errorf("unsupported theme format %q, use LoadTheme", ext)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	t, err := LoadTheme(data, unmarshal)
+	if err != nil {
+		return nil, err
+	}
+	if t.Name == "" {
+		t.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return t, nil
+}
+
+// parseThemeColor converts a color, optionally with text effects (see
+// ParseStyle), into an Attribute. Empty strings are ColorDefault.
+func parseThemeColor(s string) (Attribute, error) {
+	fg, bg, err := ParseStyle(s)
+	if err != nil {
+		return ColorDefault, err
+	}
+	if bg != ColorDefault {
+		return ColorDefault, fmt.Errorf("%q sets a background, expected a single color", s)
+	}
+	return fg, nil
+}
+
+// Theme returns the theme in use, or nil if no theme was set.
+func (g *Gui) Theme() *Theme {
+	return g.theme
+}
+
+// SetTheme applies the theme to the Gui and to all its views. It can be
+// called at any time from the main loop (use Gui.Update from other
+// goroutines) to switch themes at runtime. Colors of a view which were
+// changed by the application, i.e. that don't match the previous theme,
+// are kept. A nil theme restores the colors in place before the first
+// theme was set.
+func (g *Gui) SetTheme(t *Theme) {
+	prev := g.theme
+	if prev == nil {
+		if t == nil {
+			return
+		}
+		g.baseTheme = &Theme{
+			FgColor:         g.FgColor,
+			BgColor:         g.BgColor,
+			FrameColor:      ColorDefault,
+			TitleColor:      ColorDefault,
+			SelFgColor:      g.SelFgColor,
+			SelBgColor:      g.SelBgColor,
+			FocusFrameColor: g.SelFrameColor,
+			FocusTitleColor: g.SelFgColor,
+			FocusBgColor:    g.SelBgColor,
+		}
+		prev = g.baseTheme
+	}
+	g.theme = t
+	if t == nil {
+		t = g.baseTheme
+	}
+
+	g.FgColor, g.BgColor, g.FrameColor = t.FgColor, t.BgColor, t.FrameColor
+	g.SelFgColor, g.SelBgColor, g.SelFrameColor = t.FocusTitleColor, t.FocusBgColor, t.FocusFrameColor
+
+	for _, v := range g.views {
+		replaceColor(&v.FgColor, prev.FgColor, t.FgColor)
+		replaceColor(&v.BgColor, prev.BgColor, t.BgColor)
+		replaceColor(&v.FrameColor, prev.FrameColor, t.FrameColor)
+		replaceColor(&v.TitleColor, prev.TitleColor, t.TitleColor)
+		replaceColor(&v.SelFgColor, prev.SelFgColor, t.SelFgColor)
+		replaceColor(&v.SelBgColor, prev.SelBgColor, t.SelBgColor)
+		v.tainted = true
+	}
+}
+
+// applyTheme sets the colors of a newly created view from the theme.
+func (g *Gui) applyTheme(v *View) {
+	if g.theme == nil {
+		return
+	}
+	v.FgColor, v.BgColor = g.theme.FgColor, g.theme.BgColor
+	v.SelFgColor, v.SelBgColor = g.theme.SelFgColor, g.theme.SelBgColor
+	v.FrameColor, v.TitleColor = g.theme.FrameColor, g.theme.TitleColor
+}
+
+// replaceColor sets *dst to new if it still has the old value.
+func replaceColor(dst *Attribute, old, new Attribute) {
+	if *dst == old {
+		*dst = new
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadTheme(t *testing.T) {
+	th, err := LoadTheme([]byte(`{"name": "dark", "fg": "white", "bg": "#202020", "frame": "bold blue"}`), json.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th.Name != "dark" || th.FgColor != ColorWhite || th.BgColor != NewRGBColor(0x20, 0x20, 0x20) {
+		t.Errorf("unexpected theme %+v", th)
+	}
+	if th.FrameColor != ColorBlue|AttrBold || th.TitleColor != ColorDefault {
+		t.Errorf("unexpected frame colors %v, %v", th.FrameColor, th.TitleColor)
+	}
+
+	for _, data := range []string{
+		`{"name": `,
+		`{"fg": "nocolor"}`,
+		`{"frame": "red on blue"}`,
+	} {
+		if _, err := LoadTheme([]byte(data), json.Unmarshal); err == nil {
+			t.Errorf("%s: expected an error", data)
+		}
+	}
+}
+
+func TestLoadThemeFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gocui")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "light.json")
+	if err := ioutil.WriteFile(path, []byte(`{"fg": "black"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	th, err := LoadThemeFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th.Name != "light" || th.FgColor != ColorBlack {
+		t.Errorf("unexpected theme %+v", th)
+	}
+
+	files := map[string]string{
+		"dark.toml": "fg = \"white\"\nframe = \"bold blue\"\n",
+		"dark.yaml": "fg: white\nframe: bold blue\n",
+		"dark.yml":  "fg: \"white\"\nframe: \"bold blue\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		th, err := LoadThemeFile(path)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if th.Name != "dark" || th.FgColor != ColorWhite || th.FrameColor != ColorBlue|AttrBold {
+			t.Errorf("%s: unexpected theme %+v", name, th)
+		}
+	}
+
+	if _, err := LoadThemeFile(filepath.Join(dir, "dark.ini")); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+
+	plain, err := g.SetView("plain", 0, 0, 10, 5, 0)
+	if !errors.Is(err, ErrUnknownView) {
+		t.Fatal(err)
+	}
+	custom, err := g.SetView("custom", 0, 6, 10, 10, 0)
+	if !errors.Is(err, ErrUnknownView) {
+		t.Fatal(err)
+	}
+	custom.FgColor = ColorMagenta
+
+	dark := &Theme{FgColor: ColorWhite, BgColor: ColorBlack, FrameColor: ColorBlue, TitleColor: ColorDefault}
+	light := &Theme{FgColor: ColorBlack, BgColor: ColorWhite, FrameColor: ColorRed, TitleColor: ColorDefault}
+	for _, th := range []*Theme{dark, light} {
+		g.SetTheme(th)
+		if plain.FgColor != th.FgColor || plain.BgColor != th.BgColor || plain.FrameColor != th.FrameColor {
+			t.Errorf("expected the colors of the theme, got %v, %v, %v", plain.FgColor, plain.BgColor, plain.FrameColor)
+		}
+		if custom.FgColor != ColorMagenta {
+			t.Errorf("expected the color set by the application to be kept, got %v", custom.FgColor)
+		}
+		if custom.BgColor != th.BgColor {
+			t.Errorf("expected the background of the theme, got %v", custom.BgColor)
+		}
+	}
+	if g.Theme() != light {
+		t.Error("expected the last theme to be in use")
+	}
+
+	// a nil theme restores the colors in place before the first theme
+	g.SetTheme(nil)
+	if g.Theme() != nil {
+		t.Error("expected no theme to be in use")
+	}
+	if plain.FgColor != ColorDefault || plain.BgColor != ColorDefault || plain.FrameColor != ColorDefault {
+		t.Errorf("expected the default colors, got %v, %v, %v", plain.FgColor, plain.BgColor, plain.FrameColor)
+	}
+	if custom.FgColor != ColorMagenta {
+		t.Errorf("expected the color set by the application to be kept, got %v", custom.FgColor)
+	}
+	g.SetTheme(light)
+
+	// the views created later get the colors of the theme
+	v, err := g.SetView("new", 11, 0, 20, 5, 0)
+	if !errors.Is(err, ErrUnknownView) {
+		t.Fatal(err)
+	}
+	if v.FgColor != ColorBlack || v.BgColor != ColorWhite || v.FrameColor != ColorRed {
+		t.Errorf("expected the colors of the theme, got %v, %v, %v", v.FgColor, v.BgColor, v.FrameColor)
+	}
+}
@@ -0,0 +1,573 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"strings"
+)
+
+// TreeNode is a node of a Tree.
+type TreeNode struct {
+	// Text is the text displayed for the node.
+	Text string
+
+	// ID identifies the node among its siblings, to keep it selected and
+	// expanded when the nodes of the tree are replaced. Text is used if it
+	// is empty.
+	ID string
+
+	// Value is an arbitrary value attached to the node.
+	Value interface{}
+
+	// Children are the children of the node.
+	Children []*TreeNode
+
+	// If Lazy is true, the children of the node are loaded by the Load
+	// function of the tree when the node is expanded.
+	Lazy bool
+
+	// If Expanded is true, the children of the node are shown.
+	Expanded bool
+
+	parent *TreeNode
+
+	// loading is true while the children are loaded, and err is the error
+	// returned by the last load
+	loading bool
+	err     error
+}
+
+// Parent returns the parent of the node, or nil if it is a root. It is
+// known once the node was shown.
+func (n *TreeNode) Parent() *TreeNode {
+	return n.parent
+}
+
+// key returns the identifier of the node among its siblings.
+func (n *TreeNode) key() string {
+	if n.ID != "" {
+		return n.ID
+	}
+	return n.Text
+}
+
+// hasChildren reports whether the node can be expanded.
+func (n *TreeNode) hasChildren() bool {
+	return len(n.Children) > 0 || n.Lazy
+}
+
+// Tree markers, drawn before the text of the nodes.
+const (
+	treeCollapsed = '▸'
+	treeExpanded  = '▾'
+	treeLeaf      = '─'
+)
+
+// Tree is a Manager showing a tree of nodes in a view, one of them being
+// selected. It is navigated with the arrow keys, Page Up, Page Down, Home,
+// End and the mouse. Space, or a click on the marker of a node, expands or
+// collapses it, the right and left arrows expand and collapse the selected
+// node, or move to its first child or its parent. Typing filters the nodes
+// like in a List: the nodes containing the typed text are shown, with their
+// ancestors. Enter submits the selection.
+//
+// The children of lazy nodes are loaded by Load in a goroutine the first
+// time they are expanded, and added to the tree with Gui.Update.
+//
+// The view is created by Layout with SetView, with the name of the tree.
+// Its content must not be written, but it can be styled like any view.
+type Tree struct {
+	name           string
+	x0, y0, x1, y1 int
+	roots          []*TreeNode
+
+	// rows are the nodes shown, as last drawn
+	rows []treeRow
+
+	// selected is the selected node, and cursor its row
+	selected *TreeNode
+	cursor   int
+
+	filter string
+
+	// notified is the node selected when OnChange was last called
+	notified *TreeNode
+
+	// Load, if not nil, returns the children of a lazy node. It is called
+	// in a goroutine, and must not access the tree.
+	Load func(n *TreeNode) ([]*TreeNode, error)
+
+	// GuideFgColor is the color of the indent guides and of the markers.
+	GuideFgColor Attribute
+
+	// SelFgColor and SelBgColor are the colors of the selected node.
+	SelFgColor, SelBgColor Attribute
+
+	// OnChange, if not nil, is called by Layout when the selected node
+	// changed.
+	OnChange func(g *Gui, t *Tree) error
+
+	// OnSubmit, if not nil, is called when Enter is pressed.
+	OnSubmit func(g *Gui, t *Tree) error
+}
+
+// treeRow is a node shown in the tree.
+type treeRow struct {
+	node *TreeNode
+
+	// guides are the indent guides drawn before the marker of the node, and
+	// expanded is true if its children are shown
+	guides   string
+	expanded bool
+}
+
+// NewTree returns a tree with the given root nodes, covering the region
+// from (x0, y0) to (x1, y1).
+func NewTree(name string, x0, y0, x1, y1 int, roots ...*TreeNode) *Tree {
+	return &Tree{
+		name:         name,
+		x0:           x0,
+		y0:           y0,
+		x1:           x1,
+		y1:           y1,
+		roots:        roots,
+		GuideFgColor: ColorDefault,
+		SelFgColor:   ColorDefault | AttrReverse,
+		SelBgColor:   ColorDefault,
+	}
+}
+
+// SetDimensions moves the tree to the region from (x0, y0) to (x1, y1).
+func (t *Tree) SetDimensions(x0, y0, x1, y1 int) {
+	t.x0, t.y0, t.x1, t.y1 = x0, y0, x1, y1
+}
+
+// Roots returns the root nodes of the tree.
+func (t *Tree) Roots() []*TreeNode {
+	return t.roots
+}
+
+// SetRoots replaces the nodes of the tree. The new nodes with the same path
+// of identifiers as expanded nodes are expanded, and the one with the path
+// of the selected node is selected.
+func (t *Tree) SetRoots(roots []*TreeNode) {
+	var path []string
+	for n := t.selected; n != nil; n = n.parent {
+		path = append([]string{n.key()}, path...)
+	}
+	copyExpanded(t.roots, roots)
+	t.roots = roots
+
+	// the nearest ancestor found is selected if the node is not
+	t.selected = nil
+	nodes := roots
+	var parent *TreeNode
+	for _, key := range path {
+		n := findNode(nodes, key)
+		if n == nil {
+			break
+		}
+		n.parent = parent
+		t.selected, parent, nodes = n, n, n.Children
+	}
+}
+
+// copyExpanded expands the nodes of to whose counterparts in from are
+// expanded.
+func copyExpanded(from, to []*TreeNode) {
+	for _, n := range to {
+		if old := findNode(from, n.key()); old != nil && old.Expanded {
+			n.Expanded = true
+			copyExpanded(old.Children, n.Children)
+		}
+	}
+}
+
+// findNode returns the node with the given identifier, or nil.
+func findNode(nodes []*TreeNode, key string) *TreeNode {
+	for _, n := range nodes {
+		if n.key() == key {
+			return n
+		}
+	}
+	return nil
+}
+
+// Selected returns the selected node, or nil if no node is shown.
+func (t *Tree) Selected() *TreeNode {
+	return t.selected
+}
+
+// SetSelected selects n, and expands its ancestors. It must be a node shown
+// before, or a root.
+func (t *Tree) SetSelected(n *TreeNode) {
+	for p := n.parent; p != nil; p = p.parent {
+		p.Expanded = true
+	}
+	t.selected = n
+}
+
+// Filter returns the filter of the tree.
+func (t *Tree) Filter() string {
+	return t.filter
+}
+
+// SetFilter shows only the nodes containing s, ignoring case, and their
+// ancestors. The children of lazy nodes which were not loaded are not
+// searched.
+func (t *Tree) SetFilter(s string) {
+	t.filter = s
+}
+
+// flatten computes the rows of the tree, and loads the children of the lazy
+// nodes which are expanded.
+func (t *Tree) flatten(g *Gui) {
+	t.rows = t.rows[:0]
+	filter := strings.ToLower(t.filter)
+
+	// matches caches whether the nodes or their descendants match the
+	// filter
+	matches := make(map[*TreeNode]bool)
+	var match func(n *TreeNode) bool
+	match = func(n *TreeNode) bool {
+		m, ok := matches[n]
+		if ok {
+			return m
+		}
+		m = strings.Contains(strings.ToLower(n.Text), filter)
+		for _, c := range n.Children {
+			// every child is visited, to cache its result
+			m = match(c) || m
+		}
+		matches[n] = m
+		return m
+	}
+
+	var walk func(nodes []*TreeNode, parent *TreeNode, guides string)
+	walk = func(nodes []*TreeNode, parent *TreeNode, guides string) {
+		var shown []*TreeNode
+		for _, n := range nodes {
+			n.parent = parent
+			if filter == "" || match(n) {
+				shown = append(shown, n)
+			}
+		}
+		for i, n := range shown {
+			row := treeRow{node: n, guides: guides}
+			childGuides := ""
+			if parent != nil {
+				if i == len(shown)-1 {
+					row.guides += "└─"
+					childGuides = guides + "  "
+				} else {
+					row.guides += "├─"
+					childGuides = guides + "│ "
+				}
+			}
+
+			if n.Expanded && n.Lazy && !n.loading && t.Load != nil {
+				t.load(g, n)
+			}
+			if filter == "" {
+				row.expanded = n.Expanded && n.hasChildren()
+			} else {
+				// the matching descendants are shown
+				for _, c := range n.Children {
+					row.expanded = row.expanded || match(c)
+				}
+			}
+
+			t.rows = append(t.rows, row)
+			if row.expanded {
+				walk(n.Children, n, childGuides)
+			}
+		}
+	}
+	walk(t.roots, nil, "")
+}
+
+// load loads the children of n in a goroutine. If it fails, n is
+// collapsed, and the error is shown after its text.
+func (t *Tree) load(g *Gui, n *TreeNode) {
+	n.loading, n.err = true, nil
+	load := t.Load
+	go func() {
+		children, err := load(n)
+		g.Update(func(g *Gui) error {
+			n.loading = false
+			if err != nil {
+				n.err, n.Expanded = err, false
+				return nil
+			}
+			n.Children, n.Lazy = children, false
+			return nil
+		})
+	}()
+}
+
+// resolveSelection finds the row of the selected node. If it is not shown,
+// its nearest ancestor shown is selected, or the node at the same row.
+func (t *Tree) resolveSelection() {
+	for n := t.selected; n != nil; n = n.parent {
+		for i, r := range t.rows {
+			if r.node == n {
+				t.selected, t.cursor = n, i
+				return
+			}
+		}
+	}
+	t.move(0)
+}
+
+// move moves the selection by n rows, within the nodes shown.
+func (t *Tree) move(n int) {
+	t.cursor += n
+	if t.cursor >= len(t.rows) {
+		t.cursor = len(t.rows) - 1
+	}
+	if t.cursor < 0 {
+		t.cursor = 0
+	}
+	t.selected = nil
+	if t.cursor < len(t.rows) {
+		t.selected = t.rows[t.cursor].node
+	}
+}
+
+// Layout creates the view of the tree and draws the nodes. It calls
+// OnChange if the selection changed.
+func (t *Tree) Layout(g *Gui) error {
+	v, err := g.SetView(t.name, t.x0, t.y0, t.x1, t.y1, 0)
+	if err != nil {
+		if !errors.Is(err, ErrUnknownView) {
+			return err
+		}
+		// runes are sent to the editor, to type the filter
+		v.Editable = true
+		v.Editor = EditorFunc(t.edit)
+		if err := t.setKeybindings(g); err != nil {
+			return err
+		}
+	}
+	t.flatten(g)
+	t.resolveSelection()
+	t.draw(v)
+
+	if t.selected == t.notified {
+		return nil
+	}
+	t.notified = t.selected
+	if t.OnChange != nil {
+		return t.OnChange(g, t)
+	}
+	return nil
+}
+
+// draw writes the rows in v, and scrolls it to show the selected one.
+func (t *Tree) draw(v *View) {
+	width, height := v.Size()
+
+	lines := make([][]cell, 0, len(t.rows))
+	for i, r := range t.rows {
+		marker := treeLeaf
+		switch {
+		case r.expanded:
+			marker = treeExpanded
+		case r.node.hasChildren():
+			marker = treeCollapsed
+		case r.node.parent == nil:
+			marker = ' '
+		}
+		line := textCells(r.guides+string(marker), t.GuideFgColor, ColorDefault)
+
+		fg, bg := ColorDefault, ColorDefault
+		if i == t.cursor {
+			fg, bg = t.SelFgColor, t.SelBgColor
+		}
+		text := " " + r.node.Text
+		if r.node.loading {
+			text += " (loading…)"
+		} else if r.node.err != nil {
+			text += " (" + r.node.err.Error() + ")"
+		}
+		line = append(line, textCells(text, fg, bg)...)
+		if i == t.cursor {
+			line = padCells(line, width, fg, bg)
+		}
+		lines = append(lines, line)
+	}
+	v.lines = lines
+	v.tainted = true
+
+	if t.cursor < v.oy {
+		v.oy = t.cursor
+	}
+	if height > 0 && t.cursor >= v.oy+height {
+		v.oy = t.cursor - height + 1
+	}
+	v.cx, v.cy = 0, t.cursor
+
+	v.Subtitle = ""
+	if t.filter != "" {
+		v.Subtitle = "/" + t.filter
+	}
+}
+
+// edit types the runes in the filter.
+func (t *Tree) edit(v *View, key Key, ch rune, mod Modifier) {
+	if ch != 0 && mod == 0 {
+		t.SetFilter(t.filter + string(ch))
+	}
+}
+
+// setKeybindings sets the keybindings of the view of the tree.
+func (t *Tree) setKeybindings(g *Gui) error {
+	page := func(dir int) func(*Gui, *View) error {
+		return func(g *Gui, v *View) error {
+			_, height := v.Size()
+			if height < 1 {
+				height = 1
+			}
+			t.move(dir * height)
+			return nil
+		}
+	}
+	step := func(n int) func(*Gui, *View) error {
+		return func(*Gui, *View) error {
+			t.move(n)
+			return nil
+		}
+	}
+
+	bindings := []struct {
+		key     Key
+		handler func(*Gui, *View) error
+	}{
+		{KeyArrowUp, step(-1)},
+		{KeyArrowDown, step(1)},
+		{MouseWheelUp, step(-1)},
+		{MouseWheelDown, step(1)},
+		{KeyPgup, page(-1)},
+		{KeyPgdn, page(1)},
+		{KeyHome, t.onHome},
+		{KeyEnd, t.onEnd},
+		{KeyArrowRight, t.onRight},
+		{KeyArrowLeft, t.onLeft},
+		{KeySpace, t.onSpace},
+		{KeyBackspace, t.onBackspace},
+		{KeyBackspace2, t.onBackspace},
+		{KeyEsc, t.onEsc},
+		{KeyEnter, t.onEnter},
+		{MouseLeft, t.onClick},
+	}
+	for _, b := range bindings {
+		if err := g.SetKeybinding(t.name, b.key, ModNone, b.handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toggle expands or collapses the node of the given row.
+func (t *Tree) toggle(row int) {
+	if row >= len(t.rows) {
+		return
+	}
+	n := t.rows[row].node
+	if t.rows[row].expanded {
+		n.Expanded = false
+	} else if n.hasChildren() {
+		n.Expanded, n.err = true, nil
+	}
+}
+
+// onHome selects the first node.
+func (t *Tree) onHome(g *Gui, v *View) error {
+	t.move(-t.cursor)
+	return nil
+}
+
+// onEnd selects the last node.
+func (t *Tree) onEnd(g *Gui, v *View) error {
+	t.move(len(t.rows))
+	return nil
+}
+
+// onRight expands the selected node, or selects its first child if it is
+// expanded.
+func (t *Tree) onRight(g *Gui, v *View) error {
+	if t.cursor >= len(t.rows) {
+		return nil
+	}
+	if t.rows[t.cursor].expanded {
+		t.move(1)
+		return nil
+	}
+	t.toggle(t.cursor)
+	return nil
+}
+
+// onLeft collapses the selected node, or selects its parent if it is
+// collapsed.
+func (t *Tree) onLeft(g *Gui, v *View) error {
+	if t.cursor >= len(t.rows) {
+		return nil
+	}
+	if t.rows[t.cursor].expanded {
+		t.toggle(t.cursor)
+		return nil
+	}
+	if p := t.selected.parent; p != nil {
+		t.selected = p
+		t.resolveSelection()
+	}
+	return nil
+}
+
+// onSpace expands or collapses the selected node.
+func (t *Tree) onSpace(g *Gui, v *View) error {
+	t.toggle(t.cursor)
+	return nil
+}
+
+// onBackspace deletes the last rune of the filter.
+func (t *Tree) onBackspace(g *Gui, v *View) error {
+	if r := []rune(t.filter); len(r) > 0 {
+		t.SetFilter(string(r[:len(r)-1]))
+	}
+	return nil
+}
+
+// onEsc clears the filter.
+func (t *Tree) onEsc(g *Gui, v *View) error {
+	t.SetFilter("")
+	return nil
+}
+
+// onEnter submits the selection.
+func (t *Tree) onEnter(g *Gui, v *View) error {
+	if t.OnSubmit == nil {
+		return nil
+	}
+	return t.OnSubmit(g, t)
+}
+
+// onClick selects the clicked node, and expands or collapses it if its
+// marker was clicked. The cursor of the view was moved to the click.
+func (t *Tree) onClick(g *Gui, v *View) error {
+	_, y := v.Cursor()
+	if y >= len(t.rows) {
+		return nil
+	}
+	t.cursor = y
+	t.move(0)
+
+	mx, _ := g.MousePosition()
+	if mx-v.x0-1+v.ox == len([]rune(t.rows[y].guides)) {
+		t.toggle(y)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"testing"
+	"time"
+)
+
+func testTreeNodes() []*TreeNode {
+	return []*TreeNode{
+		{Text: "src", Expanded: true, Children: []*TreeNode{
+			{Text: "cmd", Children: []*TreeNode{{Text: "main.go"}}},
+			{Text: "pkg", Expanded: true, Children: []*TreeNode{{Text: "a.go"}, {Text: "b.go"}}},
+			{Text: "README"},
+		}},
+		{Text: "go.mod"},
+	}
+}
+
+func treeRowTexts(tree *Tree) []string {
+	var texts []string
+	for _, r := range tree.rows {
+		texts = append(texts, r.guides+r.node.Text)
+	}
+	return texts
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTreeFlatten(t *testing.T) {
+	tree := NewTree("tree", 0, 0, 20, 10, testTreeNodes()...)
+
+	tree.flatten(nil)
+	want := []string{"src", "├─cmd", "├─pkg", "│ ├─a.go", "│ └─b.go", "└─README", "go.mod"}
+	if got := treeRowTexts(tree); !equalStrings(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	// the ancestors of the matching nodes are shown, expanded
+	tree.SetFilter("MAIN")
+	tree.flatten(nil)
+	want = []string{"src", "└─cmd", "  └─main.go"}
+	if got := treeRowTexts(tree); !equalStrings(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestTreeStableSelection(t *testing.T) {
+	tree := NewTree("tree", 0, 0, 20, 10, testTreeNodes()...)
+	tree.flatten(nil)
+	tree.SetSelected(tree.rows[4].node)
+
+	// the new nodes are expanded and selected like the old ones
+	tree.SetRoots(testTreeNodes()[:1])
+	tree.roots[0].Children[1].Expanded = false
+	tree.flatten(nil)
+	tree.resolveSelection()
+	if got := tree.Selected(); got.Text != "pkg" {
+		t.Errorf("expected the ancestor of the selected node to be selected, got %q", got.Text)
+	}
+}
+
+func TestTreeLazyChildren(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := NewTree("tree", 0, 0, 20, 10, &TreeNode{Text: "root", Lazy: true})
+	loaded := make(chan struct{})
+	tree.Load = func(n *TreeNode) ([]*TreeNode, error) {
+		defer close(loaded)
+		return []*TreeNode{{Text: "child"}}, nil
+	}
+	g.SetManager(tree)
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	done := make(chan struct{})
+	g.Update(func(g *Gui) error {
+		defer close(done)
+		_, err := g.SetCurrentView("tree")
+		return err
+	})
+	<-done
+	testingScreen.WaitSync()
+
+	testingScreen.SendKeySync(KeyArrowRight)
+	select {
+	case <-loaded:
+	case <-time.After(time.Second):
+		t.Fatal("expected the children to be loaded")
+	}
+	// the children are added by an update, after Load returned
+	for i := 0; i < 100; i++ {
+		done = make(chan struct{})
+		loading := false
+		g.Update(func(g *Gui) error {
+			defer close(done)
+			loading = tree.roots[0].Lazy
+			return nil
+		})
+		<-done
+		testingScreen.WaitSync()
+		if !loading {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	want := []rune("└── child")
+	for x, r := range want {
+		if got, _ := g.Rune(x+1, 2); got != r {
+			t.Errorf("column %d: expected %q, got %q", x, r, got)
+		}
+	}
+}
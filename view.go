@@ -13,6 +13,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
 )
 
 // Constants for overlapping edges
@@ -57,6 +58,29 @@ type View struct {
 	// ei is used to decode ESC sequences on Write
 	ei *escapeInterpreter
 
+	// signs are the signs drawn in the gutter, by line
+	signs map[int]Sign
+
+	// anchor is the anchor of the view, set by Gui.SetAnchor
+	anchor *Anchor
+
+	// gutterRows are the lines of the buffer drawn on each row, -1 for the
+	// continuation lines of wrapped lines
+	gutterRows []int
+
+	// gutterCols is the width of the gutter, computed when the view is drawn
+	gutterCols int
+
+	// selection, if not nil, is drawn in reverse video
+	selection *selection
+
+	// cursors are the cursors other than the main one, at v.cx, v.cy
+	cursors []Point
+
+	// readline is the ReadlineEditor of DefaultEditor, created when the
+	// view is first edited as a single line
+	readline *ReadlineEditor
+
 	// Visible specifies whether the view is visible.
 	Visible bool
 
@@ -87,6 +111,24 @@ type View struct {
 	// If Frame is true, a border will be drawn around the view.
 	Frame bool
 
+	// ZIndex is the stacking order of the view: views are drawn by
+	// increasing ZIndex, and views with the same ZIndex in the order set by
+	// SetViewOnTop and SetViewOnBottom. Modal dialogs are always on top.
+	ZIndex int
+
+	// If Shadow is true, a drop shadow is cast by the view on the cells on
+	// its right and below it.
+	Shadow bool
+
+	// ShadowColor is the background color of the shadow. If it is
+	// ColorDefault, the shadowed cells are only dimmed.
+	ShadowColor Attribute
+
+	// If Transparent is true, the blank cells of the view which have no
+	// background color of their own are not drawn, so the views below
+	// show through them.
+	Transparent bool
+
 	// FrameColor allow to configure the color of the Frame when it is not highlighted.
 	FrameColor Attribute
 
@@ -103,8 +145,14 @@ type View struct {
 	// 11 runes which can be used with `gocui.Gui.SupportOverlaps` property.
 	//  []rune{'─', '│', '┌', '┐', '└', '┘', '├', '┤', '┬', '┴', '┼'}
 	//  []rune{'═','║','╔','╗','╚','╝','╠','╣','╦','╩','╬'}
+	// Presets are returned by FrameRunesSingle, FrameRunesRounded,
+	// FrameRunesHeavy, FrameRunesDouble, FrameRunesDashed and FrameRunesASCII.
 	FrameRunes []rune
 
+	// SelFrameRunes, if set, replaces FrameRunes for the current view when
+	// Gui.Highlight is true.
+	SelFrameRunes []rune
+
 	// PaddingX specifies the horizontal padding (left and right) inside the frame.
 	PaddingX int
 
@@ -113,22 +161,66 @@ type View struct {
 
 	// If Wrap is true, the content that is written to this View is
 	// automatically wrapped when it is longer than its width. If true the
-	// view's x-origin will be ignored.
+	// view's x-origin will be ignored. It is ignored if SingleLine is true.
 	Wrap bool
 
+	// If WordWrap is true, wrapped lines are broken after the last space
+	// that fits in the view instead of at its last column. Words longer
+	// than the view are still broken at the last column.
+	WordWrap bool
+
+	// WrapIndent is the number of columns the continuation lines of a
+	// wrapped line are indented by.
+	WrapIndent int
+
+	// WrapIndicator, if not empty, is drawn at the start of the
+	// continuation lines of a wrapped line, after WrapIndent, e.g. "↪ ".
+	WrapIndicator string
+
+	// LineNumbers selects the line numbers drawn in the gutter, on the left
+	// of the content. The continuation lines of wrapped lines have no
+	// number. The gutter is not part of the content: Size doesn't include
+	// it, and the cursor and the buffer are not affected.
+	LineNumbers LineNumbers
+
+	// If SignColumn is true, a column showing the signs set with SetSign is
+	// drawn in the gutter.
+	SignColumn bool
+
+	// GutterFgColor and GutterBgColor are the colors of the gutter. If they
+	// are ColorDefault, FgColor and BgColor are used.
+	GutterFgColor, GutterBgColor Attribute
+
 	// If Autoscroll is true, the View will automatically scroll down when the
 	// text overflows. If true the view's y-origin will be ignored.
 	Autoscroll bool
 
+	// If Bidi is true, each displayed line is reordered with the Unicode
+	// Bidirectional Algorithm, so right to left text (Hebrew, Arabic, ...)
+	// is displayed correctly. The buffer, the cursor and the editing
+	// functions keep working in logical order, and DefaultEditor moves the
+	// cursor as it is displayed with the arrow keys.
+	Bidi bool
+
 	// If Frame is true, Title allows to configure a title for the view.
 	Title string
 
+	// TitleAlign is the alignment of the title on the top edge of the frame.
+	TitleAlign Alignment
+
 	// TitleColor allow to configure the color of title and subtitle for the view.
 	TitleColor Attribute
 
 	// If Frame is true, Subtitle allows to configure a subtitle for the view.
+	// It is drawn on the right of the top edge of the frame, ending five
+	// columns before the corner.
 	Subtitle string
 
+	// If Frame is true, Labels are drawn on the edges of the frame, next to
+	// the title and the subtitle. Unlike them, labels can be drawn on the
+	// bottom edge and can be made of spans with different colors.
+	Labels []FrameLabel
+
 	// If Mask is true, the View will display the mask instead of the real
 	// content
 	Mask rune
@@ -139,6 +231,22 @@ type View struct {
 	// If HasLoader is true, the message will be appended with a spinning loader animation
 	HasLoader bool
 
+	// If SingleLine is true, the view is edited as a single line input:
+	// DefaultEditor doesn't insert newlines and has the bindings of a
+	// ReadlineEditor of the view, the newlines written with
+	// EditWrite are replaced by spaces, and the text wider than the view
+	// is scrolled horizontally. Enter calls OnSubmit.
+	SingleLine bool
+
+	// OnSubmit, if not nil, is called when Enter is pressed in an editable
+	// single line view and no keybinding handles it.
+	OnSubmit func(g *Gui, v *View) error
+
+	// Placeholder, if not empty, is drawn with PlaceholderColor when the
+	// buffer is empty.
+	Placeholder      string
+	PlaceholderColor Attribute
+
 	// KeybindOnEdit should be set to true when you want to execute keybindings even when the view is editable
 	// (this is usually not the case)
 	KeybindOnEdit bool
@@ -147,13 +255,17 @@ type View struct {
 	gui *Gui
 }
 
+// cell holds one grapheme cluster: chr is its first rune, combining the
+// following ones (combining marks, ZWJ sequences, variation selectors, ...).
 type cell struct {
 	chr              rune
+	combining        []rune
 	bgColor, fgColor Attribute
 }
 
 type cellCache struct {
 	chr              rune
+	combining        []rune
 	bgColor, fgColor Attribute
 	x, y             int
 }
@@ -162,11 +274,48 @@ type lineType []cell
 
 // String returns a string from a given cell slice.
 func (l lineType) String() string {
-	str := ""
+	var str strings.Builder
 	for _, c := range l {
-		str += string(c.chr)
+		str.WriteRune(c.chr)
+		for _, r := range c.combining {
+			str.WriteRune(r)
+		}
+	}
+	return str.String()
+}
+
+// width returns the number of columns used to display the cell.
+func (c cell) width() int {
+	if c.chr == 0 {
+		return 1 // if it's NULL character, it's translated to SPACE in setRune
 	}
-	return str
+	w := runewidth.RuneWidth(c.chr)
+	// like runewidth.StringWidth, use the first non-zero-width rune
+	for i := 0; w == 0 && i < len(c.combining); i++ {
+		w = runewidth.RuneWidth(c.combining[i])
+	}
+	return w
+}
+
+// extendsCluster reports whether r belongs to the grapheme cluster of c,
+// instead of starting a new one.
+func extendsCluster(c cell, r rune) bool {
+	// fast path, nothing below U+0300 (combining diacritical marks)
+	// extends a cluster
+	if r < 0x300 || c.chr == 0 {
+		return false
+	}
+	var str strings.Builder
+	str.WriteString(lineType{c}.String())
+	str.WriteRune(r)
+	return uniseg.GraphemeClusterCount(str.String()) == 1
+}
+
+// addToCluster appends r to the grapheme cluster of c.
+func (c *cell) addToCluster(r rune) {
+	combining := make([]rune, len(c.combining), len(c.combining)+1)
+	copy(combining, c.combining)
+	c.combining = append(combining, r)
 }
 
 // newView returns a new View object.
@@ -189,6 +338,9 @@ func (g *Gui) newView(name string, x0, y0, x1, y1 int, mode OutputMode) *View {
 	v.FgColor, v.BgColor = ColorDefault, ColorDefault
 	v.SelFgColor, v.SelBgColor = ColorDefault, ColorDefault
 	v.TitleColor, v.FrameColor = ColorDefault, ColorDefault
+	v.GutterFgColor, v.GutterBgColor = ColorDefault, ColorDefault
+	v.ShadowColor = ColorDefault
+	v.PlaceholderColor = ColorDefault | AttrDim
 	return v
 }
 
@@ -199,7 +351,7 @@ func (v *View) Dimensions() (int, int, int, int) {
 
 // Size returns the number of visible columns and rows in the View.
 func (v *View) Size() (x, y int) {
-	return v.x1 - v.x0 - 1 - 2*v.PaddingX, v.y1 - v.y0 - 1 - 2*v.PaddingY
+	return v.x1 - v.x0 - 1 - 2*v.PaddingX - v.gutterWidth(), v.y1 - v.y0 - 1 - 2*v.PaddingY
 }
 
 // Name returns the name of the view.
@@ -207,10 +359,11 @@ func (v *View) Name() string {
 	return v.name
 }
 
-// setRune sets a rune at the given point relative to the view. It applies the
+// setRune sets a rune, followed by the combining runes of its grapheme
+// cluster, at the given point relative to the view. It applies the
 // specified colors, taking into account if the cell must be highlighted. Also,
 // it checks if the position is valid.
-func (v *View) setRune(x, y int, ch rune, fgColor, bgColor Attribute) error {
+func (v *View) setRune(x, y int, ch rune, combining []rune, fgColor, bgColor Attribute) error {
 	maxX, maxY := v.Size()
 	if x < 0 || x >= maxX || y < 0 || y >= maxY {
 		return ErrInvalidPoint
@@ -220,9 +373,14 @@ func (v *View) setRune(x, y int, ch rune, fgColor, bgColor Attribute) error {
 		fgColor = v.FgColor
 		bgColor = v.BgColor
 		ch = v.Mask
+		combining = nil
 	} else if v.Highlight && y == v.cy-v.oy {
 		fgColor = v.SelFgColor | AttrBold
 		bgColor = v.SelBgColor | AttrBold
+		if v.outMode == OutputMonochrome {
+			// Without colors, the selection is shown in reverse video
+			fgColor |= AttrReverse
+		}
 	}
 
 	// Don't display NUL characters
@@ -230,7 +388,7 @@ func (v *View) setRune(x, y int, ch rune, fgColor, bgColor Attribute) error {
 		ch = ' '
 	}
 
-	tcellSetCell(v.x0+x+1+v.PaddingX, v.y0+y+1+v.PaddingY, ch, fgColor, bgColor, v.outMode)
+	tcellSetCell(v.x0+x+1+v.PaddingX+v.gutterWidth(), v.y0+y+1+v.PaddingY, ch, combining, fgColor, bgColor, v.outMode)
 
 	return nil
 }
@@ -275,7 +433,8 @@ func (v *View) SetCursor(x, y int) error {
 	return v.SetCursorUnrestricted(x, y)
 }
 
-// Cursor returns the cursor position of the view.
+// Cursor returns the cursor position of the view. Cursors returns the
+// positions of all its cursors.
 func (v *View) Cursor() (x, y int) {
 	return v.cx, v.cy
 }
@@ -430,6 +589,11 @@ func (v *View) writeRunes(p []rune) {
 			if cells == nil {
 				continue
 			}
+			// runes extending the previous grapheme cluster join its cell
+			if len(cells) == 1 && v.wx > 0 && v.wx <= len(v.lines[v.wy]) && extendsCluster(v.lines[v.wy][v.wx-1], r) {
+				v.lines[v.wy][v.wx-1].addToCluster(r)
+				continue
+			}
 			v.writeCells(v.wx, v.wy, cells)
 			v.wx += len(cells)
 		}
@@ -479,27 +643,33 @@ func (v *View) parseInput(ch rune) []cell {
 // It returns the number of bytes read into p.
 // At EOF, err will be io.EOF.
 func (v *View) Read(p []byte) (n int, err error) {
-	buffer := make([]byte, utf8.UTFMax)
+	buffer := make([]byte, 0, utf8.UTFMax)
 	offset := 0
 	if v.readBuffer != nil {
 		copy(p, v.readBuffer)
 		if len(v.readBuffer) >= len(p) {
 			if len(v.readBuffer) > len(p) {
 				v.readBuffer = v.readBuffer[len(p):]
+			} else {
+				v.readBuffer = nil
 			}
 			return len(p), nil
 		}
+		offset = len(v.readBuffer)
 		v.readBuffer = nil
 	}
 	for v.ry < len(v.lines) {
 		for v.rx < len(v.lines[v.ry]) {
-			count := utf8.EncodeRune(buffer, v.lines[v.ry][v.rx].chr)
-			copy(p[offset:], buffer[:count])
+			// a cell holds a whole grapheme cluster, which can be longer
+			// than utf8.UTFMax
+			buffer = append(buffer[:0], lineType(v.lines[v.ry][v.rx:v.rx+1]).String()...)
+			count := len(buffer)
+			copy(p[offset:], buffer)
 			v.rx++
 			newOffset := offset + count
 			if newOffset >= len(p) {
 				if newOffset > len(p) {
-					v.readBuffer = buffer[newOffset-len(p):]
+					v.readBuffer = buffer[count-(newOffset-len(p)):]
 				}
 				return len(p), nil
 			}
@@ -527,21 +697,164 @@ func (v *View) Rewind() {
 
 // viewLines returns the lines to render on the screen
 func (v *View) viewLines() [][]cell {
-	if !v.Wrap {
+	if !v.wraps() {
 		return v.lines
 	}
 
 	renderLines := [][]cell{}
-	for _, viewLine := range v.lines {
+	for _, l := range v.wrappedLines() {
+		renderLines = append(renderLines, l.cells)
+	}
+	return renderLines
+}
+
+// displayLine is a line rendered on the screen.
+type displayLine struct {
+	cells []cell
+
+	// y is the index of the line of the buffer, and x the index in it of
+	// the first cell after the prefix
+	x, y int
+
+	// prefix is the number of cells of the wrap prefix, it is only
+	// non-zero for the continuation lines of wrapped lines
+	prefix int
+
+	// continued is true for the continuation lines of wrapped lines
+	continued bool
+}
+
+// selection is a region of the buffer, from the cell (x0, y0) to the cell
+// (x1, y1) included, or the lines from y0 to y1 if lines is true.
+type selection struct {
+	x0, y0, x1, y1 int
+	lines          bool
+}
+
+// contains reports whether the cell x of the line y is selected.
+func (s *selection) contains(x, y int) bool {
+	switch {
+	case y < s.y0 || y > s.y1:
+		return false
+	case s.lines:
+		return true
+	case y == s.y0 && x < s.x0, y == s.y1 && x > s.x1:
+		return false
+	}
+	return true
+}
+
+// setSelection sets the selection drawn in the view, nil for none.
+func (v *View) setSelection(s *selection) {
+	if s == nil && v.selection == nil || s != nil && v.selection != nil && *s == *v.selection {
+		return
+	}
+	v.selection = s
+	v.tainted = true
+}
+
+// selectCells returns the cells of a display line, with the selected ones
+// and the ones of the cursors other than the main one in reverse video. A
+// space is added for these cursors at the end of the line.
+func (v *View) selectCells(dl displayLine) []cell {
+	line := make([]cell, len(dl.cells), len(dl.cells)+1)
+	copy(line, dl.cells)
+	if end := dl.x + len(line) - dl.prefix; end == len(v.lines[dl.y]) && v.cursorAt(end, dl.y) {
+		line = append(line, cell{chr: ' ', fgColor: ColorDefault, bgColor: ColorDefault})
+	}
+	for i := dl.prefix; i < len(line); i++ {
+		x := dl.x + i - dl.prefix
+		if !(v.selection != nil && v.selection.contains(x, dl.y)) && !v.cursorAt(x, dl.y) {
+			continue
+		}
+		c := &line[i]
+		if c.fgColor == ColorDefault {
+			c.fgColor = v.FgColor
+		}
+		if c.bgColor == ColorDefault {
+			c.bgColor = v.BgColor
+		}
+		c.fgColor |= AttrReverse
+	}
+	return line
+}
+
+// bidiDisplayLine returns the cells of the display line dl in visual order,
+// line being its cells as returned by selectCells. The wrap prefix stays on
+// the left, and right to left lines start with the column reserved for their
+// end, where the space of an extra cursor at the end of the line is drawn.
+func (v *View) bidiDisplayLine(dl displayLine, line []cell) []cell {
+	rtl := v.isRTL(dl.y)
+	content, end := line[dl.prefix:], []cell(nil)
+	if len(line) > len(dl.cells) {
+		content, end = line[dl.prefix:len(dl.cells)], line[len(dl.cells):]
+	}
+	visual := append([]cell{}, line[:dl.prefix]...)
+	if rtl {
+		if len(end) == 0 {
+			end = []cell{{chr: ' ', fgColor: ColorDefault, bgColor: ColorDefault}}
+		}
+		visual = append(visual, end...)
+		return append(visual, bidiVisualLine(content, rtl)...)
+	}
+	visual = append(visual, bidiVisualLine(content, rtl)...)
+	return append(visual, end...)
+}
+
+// wrappedLines returns the lines to render on the screen, with the buffer
+// line they come from.
+func (v *View) wrappedLines() []displayLine {
+	lines := make([]displayLine, 0, len(v.lines))
+	if !v.wraps() {
+		for y, l := range v.lines {
+			lines = append(lines, displayLine{cells: l, y: y})
+		}
+		return lines
+	}
+
+	prefix := v.wrapPrefix()
+	for y, viewLine := range v.lines {
+		first := true
 		for {
-			lineToRender, _, end := v.takeLine(&viewLine)
-			renderLines = append(renderLines, lineToRender)
+			x := len(v.lines[y]) - len(viewLine)
+			lineToRender, _, end := v.takeLine(&viewLine, first, v.isRTL(y))
+			l := displayLine{cells: lineToRender, x: x, y: y}
+			if !first {
+				l.cells = append(append([]cell{}, prefix...), lineToRender...)
+				l.prefix = len(prefix)
+				l.continued = true
+			}
+			lines = append(lines, l)
+			first = false
 			if end {
 				break
 			}
 		}
 	}
-	return renderLines
+	return lines
+}
+
+// wrapPrefix returns the cells drawn at the start of the continuation lines
+// of wrapped lines: WrapIndent spaces followed by WrapIndicator.
+func (v *View) wrapPrefix() []cell {
+	var prefix []cell
+	for i := 0; i < v.WrapIndent; i++ {
+		prefix = append(prefix, cell{chr: ' ', fgColor: ColorDefault, bgColor: ColorDefault})
+	}
+	for _, r := range v.WrapIndicator {
+		c := cell{chr: r, fgColor: ColorDefault, bgColor: ColorDefault}
+		if len(prefix) > 0 && extendsCluster(prefix[len(prefix)-1], r) {
+			prefix[len(prefix)-1].addToCluster(r)
+			continue
+		}
+		prefix = append(prefix, c)
+	}
+	return prefix
+}
+
+// wrapPrefixWidth returns the number of columns of the wrap prefix.
+func (v *View) wrapPrefixWidth() int {
+	return lineWidth(v.wrapPrefix())
 }
 
 // IsTainted tells us if the view is tainted
@@ -555,9 +868,10 @@ func (v *View) draw() error {
 		return nil
 	}
 
+	v.updateGutterWidth()
 	maxX, maxY := v.Size()
 
-	if v.Wrap {
+	if v.wraps() {
 		if maxX == 0 {
 			// Just return here, there is no need to try drawing chars in a too small frame
 			// Nor is it needed to return an error, there is just no space
@@ -566,24 +880,33 @@ func (v *View) draw() error {
 		v.ox = 0
 	}
 
+	if v.showsPlaceholder() {
+		v.contentCache = nil
+		v.drawPlaceholder()
+		v.drawGutter()
+		return nil
+	}
+
 	if !v.tainted && v.contentCache != nil {
 		for _, cell := range v.contentCache {
-			if err := v.setRune(cell.x, cell.y, cell.chr, cell.fgColor, cell.bgColor); err != nil {
+			if err := v.setRune(cell.x, cell.y, cell.chr, cell.combining, cell.fgColor, cell.bgColor); err != nil {
 				return err
 			}
 		}
+		v.drawGutter()
 		return nil
 	}
 
-	linesToRender := v.viewLines()
+	linesToRender := v.wrappedLines()
 
 	if v.Autoscroll && len(linesToRender) > maxY {
 		v.oy = len(linesToRender) - maxY - 1
 	}
 
 	newCache := []cellCache{}
+	v.gutterRows = v.gutterRows[:0]
 	y := 0
-	for lineIndex, line := range linesToRender {
+	for lineIndex, dl := range linesToRender {
 		if lineIndex < v.oy {
 			continue
 		}
@@ -591,14 +914,35 @@ func (v *View) draw() error {
 			break // No need to render out of screen chars
 		}
 
-		x := 0
-		for charIndex, char := range line {
-			if charIndex < v.ox {
+		if dl.continued {
+			v.gutterRows = append(v.gutterRows, -1)
+		} else {
+			v.gutterRows = append(v.gutterRows, dl.y)
+		}
+
+		line := dl.cells
+		if v.selection != nil || len(v.cursors) > 0 {
+			line = v.selectCells(dl)
+		}
+		if v.Bidi {
+			line = v.bidiDisplayLine(dl, line)
+		}
+
+		// v.ox is in columns, not in cells, as cells can be wide
+		col := 0
+		for _, char := range line {
+			charWidth := char.width()
+			x := col - v.ox
+			col += charWidth
+			if x < 0 {
 				continue
 			}
-			if x >= maxX {
+			if x+charWidth > maxX {
 				break // No need to render out of screen chars
 			}
+			if v.Transparent && char.isTransparent() {
+				continue
+			}
 
 			fgColor := char.fgColor
 			if fgColor == ColorDefault {
@@ -610,25 +954,22 @@ func (v *View) draw() error {
 			}
 
 			newCache = append(newCache, cellCache{
-				chr:     char.chr,
-				bgColor: bgColor,
-				fgColor: fgColor,
-				x:       x,
-				y:       y,
+				chr:       char.chr,
+				combining: char.combining,
+				bgColor:   bgColor,
+				fgColor:   fgColor,
+				x:         x,
+				y:         y,
 			})
-			if err := v.setRune(x, y, char.chr, fgColor, bgColor); err != nil {
+			if err := v.setRune(x, y, char.chr, char.combining, fgColor, bgColor); err != nil {
 				return err
 			}
-			if char.chr == 0 {
-				x++ // if NULL increase, so `SetWritePos` can be used (NULL translate to SPACE in setRune)
-			} else {
-				x += runewidth.RuneWidth(char.chr)
-			}
 		}
 		y++
 	}
 
 	v.contentCache = newCache
+	v.drawGutter()
 	return nil
 }
 
@@ -640,6 +981,7 @@ func (v *View) Clear() {
 	v.tainted = true
 	v.ei.reset()
 	v.lines = [][]cell{}
+	v.cursors = nil
 	v.SetCursor(0, 0)
 	v.SetOrigin(0, 0)
 	v.clearRunes()
@@ -654,8 +996,11 @@ func (v *View) linesPosOnScreen(x, y int) (viewX int, viewY int, visable bool) {
 	}
 
 	maxX, maxY := v.Size()
-	if !v.Wrap {
+	if !v.wraps() {
 		viewX = x
+		if y < len(v.lines) {
+			viewX = v.displayColumn(v.lines[y], v.isRTL(y), x)
+		}
 		viewY = y
 		visable = viewY >= v.oy && viewY < v.oy+maxY && viewX >= v.ox && viewX < v.ox+maxX
 		return
@@ -671,8 +1016,10 @@ func (v *View) linesPosOnScreen(x, y int) (viewX int, viewY int, visable bool) {
 			break
 		}
 
+		first := true
 		for {
-			_, _, end := v.takeLine(&viewLine)
+			_, _, end := v.takeLine(&viewLine, first, v.isRTL(lineIndex))
+			first = false
 			viewY++
 			if end {
 				break
@@ -681,18 +1028,40 @@ func (v *View) linesPosOnScreen(x, y int) (viewX int, viewY int, visable bool) {
 	}
 
 	if found {
+		rtl := v.isRTL(y)
+		first := true
 		for {
-			lineChars, width, end := v.takeLine(&line)
+			lenLine := len(line)
+			lineChars, width, end := v.takeLine(&line, first, rtl)
+			indent := 0
+			if !first {
+				indent = v.wrapPrefixWidth()
+			}
+			first = false
 			lenLineChars := len(lineChars)
 			if x < lenLineChars {
-				x = lineWidth(lineChars[:x])
+				x = indent + v.displayColumn(lineChars, rtl, x)
+				break
+			} else if taken := lenLine - len(line); x < taken {
+				// the cursor on the skipped space the line is broken at
+				// stays at the end of the row
+				if rtl {
+					x = indent
+				} else {
+					x = indent + width - 1
+				}
 				break
 			} else {
-				x -= lenLineChars
+				x -= taken
 			}
 
 			if end {
-				x += width
+				if rtl {
+					// the end of the line is in its reserved column
+					x = indent + v.displayColumn(lineChars, rtl, lenLineChars)
+				} else {
+					x += indent + width
+				}
 				break
 			}
 			viewY++
@@ -712,12 +1081,120 @@ func (v *View) linesPosOnScreen(x, y int) (viewX int, viewY int, visable bool) {
 	return
 }
 
+// cursorScreenPosition returns the position of the cursor on the screen,
+// and whether it is visible.
+func (v *View) cursorScreenPosition() (x, y int, visible bool) {
+	return v.screenPosition(v.cx, v.cy)
+}
+
+// screenPosition returns the screen coordinates of the cell x of the line y
+// of the buffer, and whether it is visible.
+func (v *View) screenPosition(x, y int) (sx, sy int, visible bool) {
+	viewX, viewY, visible := v.linesPosOnScreen(x, y)
+	sx = v.x0 + v.gutterWidth() + viewX + 1 - v.ox
+	sy = v.y0 + viewY + 1 - v.oy
+	return sx, sy, visible
+}
+
+// bufferPosition returns the position in the view's internal buffer of the
+// point (col, row) of the content, which is in columns and view lines
+// (i.e. taking wrapping into account) and doesn't depend on the origin.
+// Positions out of the buffer are returned as is, so they can be clamped by
+// SetCursor.
+func (v *View) bufferPosition(col, row int) (x, y int) {
+	if !v.wraps() {
+		if row < 0 || row >= len(v.lines) {
+			return col, row
+		}
+		return v.cellAtColumn(v.lines[row], v.isRTL(row), col), row
+	}
+
+	viewRow := 0
+	for y, line := range v.lines {
+		x := 0
+		first := true
+		rtl := v.isRTL(y)
+		for {
+			lenLine := len(line)
+			part, _, end := v.takeLine(&line, first, rtl)
+			if viewRow == row {
+				if !first {
+					// the wrap prefix belongs to the first cell
+					col -= v.wrapPrefixWidth()
+					if col < 0 {
+						col = 0
+					}
+				}
+				return x + v.cellAtColumn(part, rtl, col), y
+			}
+			first = false
+			x += lenLine - len(line)
+			viewRow++
+			if end {
+				break
+			}
+		}
+	}
+	return col, len(v.lines) + row - viewRow
+}
+
+// indexToColumn returns the column where the x-th cell of line starts.
+// Cells after the end of the line are one column wide.
+func indexToColumn(line []cell, x int) int {
+	if x > len(line) {
+		return lineWidth(line) + x - len(line)
+	}
+	return lineWidth(line[:x])
+}
+
+// columnToIndex returns the index of the cell of line drawn at column col.
+// It is the inverse of indexToColumn.
+func columnToIndex(line []cell, col int) int {
+	width := 0
+	for i, c := range line {
+		width += c.width()
+		if col < width {
+			return i
+		}
+	}
+	return len(line) + col - width
+}
+
+// displayColumn returns the column where the x-th cell of line is
+// displayed, taking Bidi into account. line is a buffer line or a part of
+// it, and rtl the direction of the buffer line, as returned by isRTL.
+func (v *View) displayColumn(line []cell, rtl bool, x int) int {
+	if v.Bidi {
+		return bidiIndexToColumn(line, rtl, x)
+	}
+	return indexToColumn(line, x)
+}
+
+// cellAtColumn returns the index of the cell of line displayed at column
+// col, taking Bidi into account.
+func (v *View) cellAtColumn(line []cell, rtl bool, col int) int {
+	if v.Bidi {
+		return bidiColumnToIndex(line, rtl, col)
+	}
+	return columnToIndex(line, col)
+}
+
+// isRTL reports whether the line y of the buffer is drawn as a right to left
+// paragraph. The direction of a wrapped line is the one of the whole line.
+func (v *View) isRTL(y int) bool {
+	return v.Bidi && y >= 0 && y < len(v.lines) && bidiIsRTL(v.lines[y])
+}
+
 // clearRunes erases all the cells in the view.
 func (v *View) clearRunes() {
+	if v.Transparent {
+		return
+	}
 	maxX, maxY := v.Size()
+	maxX += v.gutterWidth()
 	for x := 0; x < maxX; x++ {
 		for y := 0; y < maxY; y++ {
-			tcellSetCell(v.x0+x+1, v.y0+y+1, ' ', v.FgColor, v.BgColor, v.outMode)
+			tcellSetCell(v.x0+x+1, v.y0+y+1, ' ', nil, v.FgColor, v.BgColor, v.outMode)
 		}
 	}
 }
@@ -790,21 +1267,24 @@ func (v *View) Word(x, y int) (string, error) {
 		return "", ErrInvalidPoint
 	}
 
-	str := lineType(v.lines[y]).String()
+	line := v.lines[y]
+	nl, nr := wordBounds(line, x)
+	return lineType(line[nl:nr]).String(), nil
+}
 
-	nl := strings.LastIndexFunc(str[:x], indexFunc)
-	if nl == -1 {
-		nl = 0
-	} else {
-		nl = nl + 1
+// wordBounds returns the cells of the word of line at the cell x, from nl
+// to nr excluded. x can be the end of the line.
+func wordBounds(line []cell, x int) (nl, nr int) {
+	// work on cells, x is a cell index and a cell can hold several runes
+	nl = x
+	for nl > 0 && !indexFunc(line[nl-1].chr) {
+		nl--
 	}
-	nr := strings.IndexFunc(str[x:], indexFunc)
-	if nr == -1 {
-		nr = len(str)
-	} else {
-		nr = nr + x
+	nr = x
+	for nr < len(line) && !indexFunc(line[nr].chr) {
+		nr++
 	}
-	return string(str[nl:nr]), nil
+	return nl, nr
 }
 
 // indexFunc allows to split lines by words taking into account spaces
@@ -844,6 +1324,9 @@ func (v *View) SetHighlight(y int, on bool) error {
 		if on {
 			c.bgColor = v.SelBgColor
 			c.fgColor = v.SelFgColor
+			if v.outMode == OutputMonochrome {
+				c.fgColor |= AttrReverse
+			}
 		} else {
 			c.bgColor = v.BgColor
 			c.fgColor = v.FgColor
@@ -857,18 +1340,57 @@ func (v *View) SetHighlight(y int, on bool) error {
 
 func lineWidth(line []cell) (n int) {
 	for i := range line {
-		if line[i].chr == 0 {
-			n++ // if it's NULL character, it's translated to SPACE in setRune
-		} else {
-			n += runewidth.RuneWidth(line[i].chr)
-		}
+		n += line[i].width()
 	}
 
 	return
 }
 
-// takeLine slices one visable line from l and returns the sliced part
-func (v *View) takeLine(l *[]cell) (visableLine []cell, width int, end bool) {
+// textCells returns the cells of s, one per grapheme cluster, with the
+// given colors.
+func textCells(s string, fgColor, bgColor Attribute) []cell {
+	var cells []cell
+	gr := uniseg.NewGraphemes(s)
+	for gr.Next() {
+		runes := gr.Runes()
+		c := cell{chr: runes[0], fgColor: fgColor, bgColor: bgColor}
+		if len(runes) > 1 {
+			c.combining = runes[1:]
+		}
+		cells = append(cells, c)
+	}
+	return cells
+}
+
+// truncateCells returns the first cells of line which fit in width columns.
+func truncateCells(line []cell, width int) []cell {
+	w := 0
+	for i, c := range line {
+		w += c.width()
+		if w > width {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// padCells appends to line blank cells with the given colors, up to width
+// columns.
+func padCells(line []cell, width int, fgColor, bgColor Attribute) []cell {
+	for w := lineWidth(line); w < width; w++ {
+		line = append(line, cell{chr: ' ', fgColor: fgColor, bgColor: bgColor})
+	}
+	return line
+}
+
+// takeLine slices one visable line from l and returns the sliced part.
+// first is false for the continuation lines of a wrapped line, which are
+// narrower if there is a wrap prefix. rtl is true for the right to left
+// lines, which are narrower by the column reserved for the end of the line
+// (see bidiIndexToColumn). With WordWrap, the line is broken after the last
+// space that fits, if any. If it is broken at a space which doesn't fit, the
+// space is taken from l but isn't part of visableLine.
+func (v *View) takeLine(l *[]cell, first, rtl bool) (visableLine []cell, width int, end bool) {
 	if l == nil {
 		panic("take line l can't be nil")
 	}
@@ -882,35 +1404,58 @@ func (v *View) takeLine(l *[]cell) (visableLine []cell, width int, end bool) {
 	}
 
 	maxX, _ := v.Size()
-	i := 0
-	cell := cell{}
+	if !first {
+		maxX -= v.wrapPrefixWidth()
+	}
+	if rtl {
+		maxX--
+	}
+	if maxX < 1 {
+		maxX = 1
+	}
 
-	for i, cell = range *l {
-		chr := cell.chr
-		charWidth := 1 // default for NULL character (translated to SPACE in setRune)
-		if chr != 0 {
-			charWidth = runewidth.RuneWidth(chr)
-		}
+	n := 0
+	for n < len(*l) {
+		charWidth := (*l)[n].width()
 
-		if width+charWidth > maxX {
-			i-- // decrease as this character is not included
+		// a character wider than the view is taken anyway, or we would
+		// never make progress
+		if width > 0 && width+charWidth > maxX {
 			break
 		}
-
 		width += charWidth
-		visableLine = append(visableLine, cell)
-		if width == maxX {
-			break
+		n++
+	}
+
+	taken := n
+	if v.WordWrap && n < len(*l) {
+		if isWrapSpace((*l)[n]) {
+			// the space the line is broken at is skipped, so that the
+			// next line starts with a word
+			taken = n + 1
+		} else {
+			for b := n; b > 0; b-- {
+				if isWrapSpace((*l)[b-1]) {
+					n, taken = b, b
+					break
+				}
+			}
 		}
 	}
 
-	i++
-	end = i == len(*l)
-	*l = (*l)[i:]
+	visableLine = append(visableLine, (*l)[:n]...)
+	width = lineWidth(visableLine)
+	end = taken == len(*l)
+	*l = (*l)[taken:]
 
 	return
 }
 
+// isWrapSpace reports whether a line can be broken after c with WordWrap.
+func isWrapSpace(c cell) bool {
+	return c.chr == ' ' || c.chr == 0 || c.chr == '\t'
+}
+
 func linesToString(lines [][]cell) string {
 	str := make([]string, len(lines))
 	for i := range lines {
@@ -0,0 +1,244 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestViewGraphemeClusters(t *testing.T) {
+	tests := []struct {
+		text  string
+		cells int
+	}{
+		{"abc", 3},
+		{"éa", 2},    // combining accent
+		{"👨‍👩‍👧x", 2}, // ZWJ sequence
+		{"日本語", 3},
+		{"🇫🇷🇩🇪", 2}, // regional indicators
+	}
+
+	for _, tt := range tests {
+		v := (&Gui{}).newView("v", 0, 0, 11, 5, OutputNormal)
+		fmt.Fprint(v, tt.text)
+		if got := len(v.lines[0]); got != tt.cells {
+			t.Errorf("%q: expected %d cells, got %d", tt.text, tt.cells, got)
+		}
+		if got := v.Buffer(); got != tt.text {
+			t.Errorf("%q: buffer is %q", tt.text, got)
+		}
+		line, _ := v.Line(0)
+		if line != tt.text {
+			t.Errorf("%q: line is %q", tt.text, line)
+		}
+	}
+}
+
+func TestViewWideCharacterColumns(t *testing.T) {
+	v := (&Gui{}).newView("v", 0, 0, 11, 5, OutputNormal)
+	fmt.Fprint(v, "日本語abc")
+
+	x, y, visible := v.linesPosOnScreen(2, 0)
+	if x != 4 || y != 0 || !visible {
+		t.Errorf("expected cursor at column 4, got %d, %d, %v", x, y, visible)
+	}
+	x, _ = v.bufferPosition(3, 0)
+	if x != 1 {
+		t.Errorf("expected column 3 to be the cell 1, got %d", x)
+	}
+	x, _ = v.bufferPosition(7, 0)
+	if x != 4 {
+		t.Errorf("expected column 7 to be the cell 4, got %d", x)
+	}
+
+	// 10 columns: the third CJK character doesn't fit on the first line
+	v.Clear()
+	v.Wrap = true
+	fmt.Fprint(v, "abcde日本語xyz")
+	lines := v.ViewBufferLines()
+	if len(lines) != 2 || lines[0] != "abcde日本" || lines[1] != "語xyz" {
+		t.Errorf("unexpected wrapped lines %q", lines)
+	}
+	x, y, _ = v.linesPosOnScreen(7, 0)
+	if x != 0 || y != 1 {
+		t.Errorf("expected cursor at 0, 1, got %d, %d", x, y)
+	}
+	x, y = v.bufferPosition(2, 1)
+	if x != 8 || y != 0 {
+		t.Errorf("expected buffer position 8, 0, got %d, %d", x, y)
+	}
+}
+
+func TestViewEditGraphemeClusters(t *testing.T) {
+	v := (&Gui{}).newView("v", 0, 0, 11, 5, OutputNormal)
+	for _, r := range "café 日本" {
+		v.EditWrite(r)
+	}
+	if got := v.Buffer(); got != "café 日本" {
+		t.Errorf("unexpected buffer %q", got)
+	}
+	if x, _ := v.Cursor(); x != 7 {
+		t.Errorf("expected cursor after 7 cells, got %d", x)
+	}
+
+	// deleting removes the whole cluster
+	v.SetCursor(4, 0)
+	v.EditDelete(true)
+	if got := v.Buffer(); got != "caf 日本" {
+		t.Errorf("unexpected buffer %q", got)
+	}
+
+	word, err := v.Word(5, 0)
+	if err != nil || word != "日本" {
+		t.Errorf("expected word %q, got %q (%v)", "日本", word, err)
+	}
+}
+
+func TestViewWideTitle(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetManagerFunc(func(g *Gui) error {
+		if v, err := g.SetView("v", 0, 0, 10, 3, 0); err != nil {
+			if !errors.Is(err, ErrUnknownView) {
+				return err
+			}
+			v.Title = "日本語の題名"
+		}
+		return nil
+	})
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	// the title has 7 columns available: 3 CJK characters and an ellipsis
+	want := []rune{'┌', '─', '日', 0, '本', 0, '語', 0, '…', '─', '┐'}
+	for x, r := range want {
+		if r == 0 {
+			continue
+		}
+		if got, _ := g.Rune(x, 0); got != r {
+			t.Errorf("column %d: expected %q, got %q", x, r, got)
+		}
+	}
+}
+
+func TestViewWordWrap(t *testing.T) {
+	v := (&Gui{}).newView("v", 0, 0, 11, 5, OutputNormal)
+	v.Wrap = true
+	v.WordWrap = true
+	fmt.Fprint(v, "the quick brown fox jumps\nabcdefghijklmno")
+
+	want := []string{"the quick ", "brown fox ", "jumps", "abcdefghij", "klmno"}
+	if got := v.ViewBufferLines(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected lines %q, got %q", want, got)
+	}
+
+	v.WrapIndent = 2
+	v.WrapIndicator = "> "
+	v.Clear()
+	fmt.Fprint(v, "the quick brown fox")
+	want = []string{"the quick ", "  > brown ", "  > fox"}
+	if got := v.ViewBufferLines(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected lines %q, got %q", want, got)
+	}
+
+	// the cursor on 'f' is displayed after the prefix
+	x, y, _ := v.linesPosOnScreen(16, 0)
+	if x != 4 || y != 2 {
+		t.Errorf("expected cursor at 4, 2, got %d, %d", x, y)
+	}
+	for _, col := range []int{1, 4} {
+		if x, y := v.bufferPosition(col, 2); x != 16 || y != 0 {
+			t.Errorf("expected column %d to be the cell 16, got %d, %d", col, x, y)
+		}
+	}
+	v.SetCursor(15, 0)
+	v.MoveCursor(1, 0)
+	if x, y := v.Cursor(); x != 16 || y != 0 {
+		t.Errorf("expected cursor at 16, 0, got %d, %d", x, y)
+	}
+
+	// the space the line is broken at doesn't fit: it isn't displayed, and
+	// the cursor on it stays on the row of the word before it
+	v = (&Gui{}).newView("v", 0, 0, 11, 5, OutputNormal)
+	v.Wrap = true
+	v.WordWrap = true
+	fmt.Fprint(v, "the quickk brown")
+	want = []string{"the quickk", "brown"}
+	if got := v.ViewBufferLines(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected lines %q, got %q", want, got)
+	}
+	for _, tt := range []struct{ x, col, row int }{{9, 9, 0}, {10, 9, 0}, {11, 0, 1}, {16, 5, 1}} {
+		if col, row, _ := v.linesPosOnScreen(tt.x, 0); col != tt.col || row != tt.row {
+			t.Errorf("cell %d: expected (%d, %d), got (%d, %d)", tt.x, tt.col, tt.row, col, row)
+		}
+	}
+	if x, y := v.bufferPosition(0, 1); x != 11 || y != 0 {
+		t.Errorf("expected column 0 of row 1 to be the cell 11, got %d, %d", x, y)
+	}
+}
+
+func TestViewGutter(t *testing.T) {
+	g, err := NewGui(OutputSimulator, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetManagerFunc(func(g *Gui) error {
+		if v, err := g.SetView("v", 0, 0, 12, 5, 0); err != nil {
+			if !errors.Is(err, ErrUnknownView) {
+				return err
+			}
+			v.LineNumbers = LineNumbersRelative
+			v.SignColumn = true
+			fmt.Fprint(v, "a\nb\nc")
+			v.SetCursor(0, 1)
+			v.SetSign(2, Sign{Rune: 'E', FgColor: ColorRed})
+		}
+		return nil
+	})
+	testingScreen := g.GetTestingScreen()
+	cleanup := testingScreen.StartGui()
+	defer cleanup()
+
+	// sign column, line number and separator, then the content
+	want := []string{" 1 a", " 2 b", "E1 c", "    "}
+	for y, row := range want {
+		for x, r := range row {
+			if got, _ := g.Rune(x+1, y+1); got != r {
+				t.Errorf("%d, %d: expected %q, got %q", x+1, y+1, r, got)
+			}
+		}
+	}
+
+	v, _ := g.View("v")
+	if w, _ := v.Size(); w != 8 {
+		t.Errorf("expected the gutter to be excluded from the size, got %d", w)
+	}
+	if x, y := v.Cursor(); x != 0 || y != 1 {
+		t.Errorf("expected cursor at 0, 1, got %d, %d", x, y)
+	}
+
+	// the gutter is widened when the view is drawn with 10 lines
+	done := make(chan struct{})
+	g.Update(func(g *Gui) error {
+		defer close(done)
+		fmt.Fprint(v, "\nd\ne\nf\ng\nh\ni\nj")
+		return nil
+	})
+	<-done
+	testingScreen.WaitSync()
+	if w, _ := v.Size(); w != 7 {
+		t.Errorf("expected the gutter to be 5 columns wide, got %d", 12-1-w)
+	}
+	for x, r := range "  1 a" {
+		if got, _ := g.Rune(x+1, 1); got != r {
+			t.Errorf("%d, 1: expected %q, got %q", x+1, r, got)
+		}
+	}
+}
@@ -0,0 +1,1062 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// VimMode is the mode of a VimEditor.
+type VimMode int
+
+// The modes of a VimEditor.
+const (
+	VimNormal VimMode = iota
+	VimInsert
+	VimVisual
+	VimVisualLine
+)
+
+// String returns the name of the mode, e.g. "INSERT".
+func (m VimMode) String() string {
+	switch m {
+	case VimInsert:
+		return "INSERT"
+	case VimVisual:
+		return "VISUAL"
+	case VimVisualLine:
+		return "VISUAL LINE"
+	}
+	return "NORMAL"
+}
+
+// VimEditor is a modal Editor implementing a subset of the commands of vim.
+// It starts in normal mode, where the keys are commands:
+//
+//	h j k l w b e 0 ^ $ gg G   motions, which can be preceded by a count
+//	d c y                      operators, followed by a motion, a text object
+//	                           (iw aw i" a" i' a' i( a( i[ a[ i{ a{ i< a<)
+//	                           or repeated to operate on lines, e.g. 2dw, dd
+//	x X D C Y p P r            shortcuts, put and replace
+//	i a I A o O                enter insert mode
+//	v V                        enter visual and visual line modes
+//	"x                         use the register x for the next command
+//
+// In insert mode, the keys are sent to Editor, and Esc returns to normal
+// mode. In visual mode, the motions and the text objects extend the
+// selection, o moves to its other end, and d, c and y operate on it.
+//
+// Several cursors (see View.Cursors) are only used in insert mode, entered
+// with i: Esc and the other commands remove the cursors but the main one.
+//
+// The deleted and yanked text is stored in the unnamed register ("), and
+// the yanked text in the register 0 too. The registers a to z are set and
+// put with a register prefix, the registers A to Z append to them, and the
+// register _ discards the text.
+type VimEditor struct {
+	// Editor edits the text in insert mode, DefaultEditor is used if it
+	// is nil.
+	Editor Editor
+
+	// OnModeChange, if not nil, is called when the mode changes, e.g. to
+	// show it in a status bar.
+	OnModeChange func(v *View, mode VimMode)
+
+	mode VimMode
+
+	// keys are the keys of the command being typed
+	keys []rune
+
+	registers map[rune]vimRegister
+
+	// anchor is the other end of the selection in visual mode
+	anchor vimPos
+
+	// want is the cell kept by the vertical motions, -1 for the end of the
+	// lines, while the cursor stays where vertical left it
+	want     int
+	vertical vimPos
+}
+
+// vimPos is a position in the buffer: the cell x of the line y. x can be
+// the length of the line, for its end.
+type vimPos struct {
+	x, y int
+}
+
+// less reports whether p is before q.
+func (p vimPos) less(q vimPos) bool {
+	return p.y < q.y || p.y == q.y && p.x < q.x
+}
+
+// vimRange is the range of text of a command, from start to end excluded,
+// or the lines from start.y to end.y if lines is true.
+type vimRange struct {
+	start, end vimPos
+	lines      bool
+}
+
+// vimRegister is the text of a register. If lines is true, the text is made
+// of whole lines, without the last newline.
+type vimRegister struct {
+	text  string
+	lines bool
+}
+
+// vimCommand is a command typed in normal or visual mode.
+type vimCommand struct {
+	register rune
+	count    int
+	op       rune
+	name     string
+	arg      rune
+}
+
+// The kinds of motions: an exclusive motion doesn't include the cell it
+// moves to, an inclusive one does, and a linewise one includes whole lines.
+const (
+	vimExclusive = iota
+	vimInclusive
+	vimLinewise
+)
+
+var (
+	vimMotions       = []string{"h", "j", "k", "l", " ", "w", "b", "e", "0", "^", "$", "gg", "G", "_"}
+	vimNormalActions = []string{"i", "a", "I", "A", "o", "O", "x", "X", "D", "C", "Y", "p", "P", "v", "V"}
+	vimVisualActions = []string{"d", "x", "c", "s", "y", "o", "v", "V"}
+	vimTextObjects   []string
+
+	// vimKeys are the keys used as commands in normal mode
+	vimKeys = map[Key]rune{
+		KeyArrowLeft:  'h',
+		KeyArrowDown:  'j',
+		KeyArrowUp:    'k',
+		KeyArrowRight: 'l',
+		KeyBackspace:  'h',
+		KeyBackspace2: 'h',
+		KeySpace:      ' ',
+	}
+
+	// vimShortcuts are the commands made of an operator and a motion
+	vimShortcuts = map[string]vimCommand{
+		"x": {op: 'd', name: "l"},
+		"X": {op: 'd', name: "h"},
+		"D": {op: 'd', name: "$"},
+		"C": {op: 'c', name: "$"},
+		"Y": {op: 'y', name: "_"},
+	}
+
+	// vimVisualOperators are the operators of the visual mode commands
+	vimVisualOperators = map[string]rune{"d": 'd', "x": 'd', "c": 'c', "s": 'c', "y": 'y'}
+
+	// vimBrackets are the closing brackets of the bracket text objects
+	vimBrackets = map[rune]rune{'(': ')', ')': ')', 'b': ')', '[': ']', ']': ']', '{': '}', '}': '}', 'B': '}', '<': '>', '>': '>'}
+)
+
+func init() {
+	for _, r := range "w\"'`()b[]{}B<>" {
+		vimTextObjects = append(vimTextObjects, "i"+string(r), "a"+string(r))
+	}
+}
+
+// NewVimEditor returns an editor in normal mode. A view needs its own
+// editor.
+func NewVimEditor() *VimEditor {
+	return &VimEditor{registers: make(map[rune]vimRegister)}
+}
+
+// Mode returns the mode of the editor.
+func (e *VimEditor) Mode() VimMode {
+	return e.mode
+}
+
+// SetMode sets the mode of the editor of v. The visual modes select from the
+// cursor.
+func (e *VimEditor) SetMode(v *View, mode VimMode) {
+	e.keys = e.keys[:0]
+	if mode == VimVisual || mode == VimVisualLine {
+		e.anchor = vimPos{v.cx, v.cy}
+	}
+	e.setMode(v, mode)
+	if mode != VimInsert {
+		e.clampCursor(v)
+	}
+	e.updateSelection(v)
+}
+
+// Register returns the text of a register, e.g. '"' for the unnamed
+// register. The text of linewise registers ends with a newline.
+func (e *VimEditor) Register(name rune) string {
+	reg := e.registers[unicode.ToLower(name)]
+	if reg.lines {
+		return reg.text + "\n"
+	}
+	return reg.text
+}
+
+// SetRegister sets the text of a register. The text is put linewise if it
+// ends with a newline.
+func (e *VimEditor) SetRegister(name rune, text string) {
+	if e.registers == nil {
+		e.registers = make(map[rune]vimRegister)
+	}
+	if strings.HasSuffix(text, "\n") {
+		e.registers[unicode.ToLower(name)] = vimRegister{strings.TrimSuffix(text, "\n"), true}
+		return
+	}
+	e.registers[unicode.ToLower(name)] = vimRegister{text: text}
+}
+
+// Edit runs the commands typed in normal and visual modes, and sends the
+// keys to the editor in insert mode.
+func (e *VimEditor) Edit(v *View, key Key, ch rune, mod Modifier) {
+	if e.mode == VimInsert {
+		if key == KeyEsc {
+			v.ClearCursors()
+			e.setMode(v, VimNormal)
+			e.setCursor(v, v.cx-1, v.cy)
+			e.clampCursor(v)
+			return
+		}
+		e.editor().Edit(v, key, ch, mod)
+		return
+	}
+
+	if key == KeyEsc {
+		v.ClearCursors()
+		if len(e.keys) == 0 && e.mode != VimNormal {
+			e.setMode(v, VimNormal)
+			e.updateSelection(v)
+		}
+		e.keys = e.keys[:0]
+		return
+	}
+	if ch == 0 {
+		ch = vimKeys[key]
+	}
+	if ch == 0 || mod != ModNone {
+		e.keys = e.keys[:0]
+		return
+	}
+
+	e.keys = append(e.keys, ch)
+	cmd, done, ok := parseVimCommand(e.keys, e.mode != VimNormal)
+	if !ok || done {
+		e.keys = e.keys[:0]
+	}
+	if !ok || !done {
+		return
+	}
+
+	if e.mode != VimNormal || cmd.name != "i" {
+		v.ClearCursors()
+	}
+	if e.mode == VimNormal {
+		e.normal(v, cmd)
+	} else {
+		e.visual(v, cmd)
+	}
+	if e.mode != VimInsert {
+		e.clampCursor(v)
+	}
+	switch cmd.name {
+	case "$":
+		e.want = -1
+		fallthrough
+	case "j", "k":
+		e.vertical = vimPos{v.cx, v.cy}
+	}
+	e.updateSelection(v)
+}
+
+// Paste sends the text to the editor in insert mode. In the other modes, it
+// is inserted before the cursor, which moves on its last character.
+func (e *VimEditor) Paste(v *View, text string) {
+	if e.mode == VimInsert {
+		pasteText(e.editor(), v, text)
+		return
+	}
+	e.SetMode(v, VimNormal)
+	v.ClearCursors()
+	v.EditPaste(text)
+	e.setCursor(v, v.cx-1, v.cy)
+	e.clampCursor(v)
+}
+
+// editor returns the editor of the insert mode.
+func (e *VimEditor) editor() Editor {
+	if e.Editor == nil {
+		return DefaultEditor
+	}
+	return e.Editor
+}
+
+// parseVimCommand parses the keys typed in normal mode, or in visual mode
+// if visual is true. It returns ok false if the keys are not a command, and
+// done false if the command is not complete.
+func parseVimCommand(keys []rune, visual bool) (cmd vimCommand, done, ok bool) {
+	i := 0
+	count := func() {
+		n := 0
+		for i < len(keys) && (keys[i] >= '1' && keys[i] <= '9' || n > 0 && keys[i] == '0') {
+			n = n*10 + int(keys[i]-'0')
+			i++
+		}
+		if n > 0 && cmd.count > 0 {
+			cmd.count *= n
+		} else if n > 0 {
+			cmd.count = n
+		}
+	}
+
+	count()
+	if i < len(keys) && keys[i] == '"' {
+		if i+1 == len(keys) {
+			return cmd, false, true
+		}
+		cmd.register = keys[i+1]
+		i += 2
+		count()
+	}
+	if i == len(keys) {
+		return cmd, false, true
+	}
+
+	names := vimMotions
+	switch {
+	case visual:
+		names = append(append(append([]string{}, names...), vimTextObjects...), vimVisualActions...)
+	case strings.ContainsRune("dcy", keys[i]):
+		cmd.op = keys[i]
+		i++
+		count()
+		if i == len(keys) {
+			return cmd, false, true
+		}
+		if keys[i] == cmd.op && i+1 == len(keys) {
+			// dd operates on lines, like d_
+			cmd.name = "_"
+			return cmd, true, true
+		}
+		names = append(append([]string{}, names...), vimTextObjects...)
+	case keys[i] == 'r':
+		if i+1 == len(keys) {
+			return cmd, false, true
+		}
+		cmd.name, cmd.arg = "r", keys[i+1]
+		return cmd, i+2 == len(keys), i+2 == len(keys)
+	default:
+		names = append(append([]string{}, names...), vimNormalActions...)
+	}
+
+	rest := string(keys[i:])
+	for _, name := range names {
+		if name == rest {
+			cmd.name = name
+			return cmd, true, true
+		}
+	}
+	for _, name := range names {
+		if strings.HasPrefix(name, rest) {
+			return cmd, false, true
+		}
+	}
+	return cmd, false, false
+}
+
+// setMode sets the mode, and calls OnModeChange if it changed.
+func (e *VimEditor) setMode(v *View, mode VimMode) {
+	if mode == e.mode {
+		return
+	}
+	e.mode = mode
+	if e.OnModeChange != nil {
+		e.OnModeChange(v, mode)
+	}
+}
+
+// normal runs a command in normal mode.
+func (e *VimEditor) normal(v *View, cmd vimCommand) {
+	n := cmd.count
+	if n == 0 {
+		n = 1
+	}
+	line := e.line(v, v.cy)
+
+	switch {
+	case cmd.op != 0:
+		if r, ok := e.commandRange(v, cmd); ok {
+			e.operate(v, cmd.op, cmd.register, r)
+		}
+	case cmd.name == "i":
+		e.setMode(v, VimInsert)
+	case cmd.name == "a":
+		if len(line) > 0 {
+			e.setCursor(v, v.cx+1, v.cy)
+		}
+		e.setMode(v, VimInsert)
+	case cmd.name == "I":
+		e.setCursor(v, firstNonBlank(line), v.cy)
+		e.setMode(v, VimInsert)
+	case cmd.name == "A":
+		e.setCursor(v, len(line), v.cy)
+		e.setMode(v, VimInsert)
+	case cmd.name == "o" || cmd.name == "O":
+		if v.SingleLine {
+			return
+		}
+		e.ensureLine(v)
+		y := v.cy
+		if cmd.name == "o" {
+			y++
+		}
+		v.lines = append(v.lines[:y:y], append([][]cell{nil}, v.lines[y:]...)...)
+		v.tainted = true
+		e.setCursor(v, 0, y)
+		e.setMode(v, VimInsert)
+	case cmd.name == "x", cmd.name == "X", cmd.name == "D", cmd.name == "C", cmd.name == "Y":
+		shortcut := vimShortcuts[cmd.name]
+		shortcut.register, shortcut.count = cmd.register, cmd.count
+		e.normal(v, shortcut)
+	case cmd.name == "p" || cmd.name == "P":
+		e.put(v, cmd.register, n, cmd.name == "P")
+	case cmd.name == "r":
+		if v.cx+n > len(line) {
+			return
+		}
+		for x := v.cx; x < v.cx+n; x++ {
+			line[x].chr, line[x].combining = cmd.arg, nil
+		}
+		v.tainted = true
+		e.setCursor(v, v.cx+n-1, v.cy)
+	case cmd.name == "v":
+		e.SetMode(v, VimVisual)
+	case cmd.name == "V":
+		e.SetMode(v, VimVisualLine)
+	default:
+		if p, _, ok := e.motion(v, cmd.name, cmd.count); ok {
+			e.setCursor(v, p.x, p.y)
+		}
+	}
+}
+
+// visual runs a command in visual mode.
+func (e *VimEditor) visual(v *View, cmd vimCommand) {
+	switch cmd.name {
+	case "d", "x", "c", "s", "y":
+		r := e.selectionRange(v)
+		e.setMode(v, VimNormal)
+		e.operate(v, vimVisualOperators[cmd.name], cmd.register, r)
+	case "o":
+		cursor := vimPos{v.cx, v.cy}
+		e.setCursor(v, e.anchor.x, e.anchor.y)
+		e.anchor = cursor
+	case "v", "V":
+		mode := VimVisual
+		if cmd.name == "V" {
+			mode = VimVisualLine
+		}
+		if mode == e.mode {
+			mode = VimNormal
+		}
+		e.setMode(v, mode)
+	default:
+		if len(cmd.name) == 2 && (cmd.name[0] == 'i' || cmd.name[0] == 'a') {
+			if r, ok := e.textObject(v, cmd.name); ok && r.start != r.end {
+				end, _ := vimPrev(v.lines, r.end)
+				e.anchor = r.start
+				e.setCursor(v, end.x, end.y)
+			}
+			return
+		}
+		if p, _, ok := e.motion(v, cmd.name, cmd.count); ok {
+			e.setCursor(v, p.x, p.y)
+		}
+	}
+}
+
+// selectionRange returns the range selected in visual mode.
+func (e *VimEditor) selectionRange(v *View) vimRange {
+	start, end := e.anchor, vimPos{v.cx, v.cy}
+	if end.less(start) {
+		start, end = end, start
+	}
+	if e.mode == VimVisualLine {
+		return vimRange{start: start, end: end, lines: true}
+	}
+	if end.x < len(e.line(v, end.y)) {
+		end.x++
+	}
+	return vimRange{start: start, end: end}
+}
+
+// updateSelection shows the selection in v in visual mode.
+func (e *VimEditor) updateSelection(v *View) {
+	if e.mode != VimVisual && e.mode != VimVisualLine {
+		v.setSelection(nil)
+		return
+	}
+	start, end := e.anchor, vimPos{v.cx, v.cy}
+	if end.less(start) {
+		start, end = end, start
+	}
+	v.setSelection(&selection{x0: start.x, y0: start.y, x1: end.x, y1: end.y, lines: e.mode == VimVisualLine})
+}
+
+// motion returns the position a motion moves the cursor to, repeated count
+// times, and the kind of the motion.
+func (e *VimEditor) motion(v *View, name string, count int) (p vimPos, kind int, ok bool) {
+	if len(v.lines) == 0 {
+		return p, vimExclusive, false
+	}
+	n := count
+	if n == 0 {
+		n = 1
+	}
+	p = vimPos{v.cx, v.cy}
+	last := len(v.lines) - 1
+
+	switch name {
+	case "h":
+		p.x -= n
+		if p.x < 0 {
+			p.x = 0
+		}
+	case "l", " ":
+		p.x += n
+		if l := len(v.lines[p.y]); p.x > l {
+			p.x = l
+		}
+	case "j", "k":
+		if p != e.vertical {
+			e.want = p.x
+		}
+		if name == "j" {
+			p.y += n
+		} else {
+			p.y -= n
+		}
+		p.y = clampInt(p.y, 0, last)
+		p.x = e.wantedCell(v.lines[p.y])
+		return p, vimLinewise, true
+	case "w", "b", "e":
+		for i := 0; i < n; i++ {
+			switch name {
+			case "w":
+				p = vimWordForward(v.lines, p)
+			case "b":
+				p = vimWordBackward(v.lines, p)
+			case "e":
+				p = vimWordEnd(v.lines, p)
+			}
+		}
+		if name == "e" {
+			return p, vimInclusive, true
+		}
+	case "0":
+		p.x = 0
+	case "^":
+		p.x = firstNonBlank(v.lines[p.y])
+	case "$":
+		p.y = clampInt(p.y+n-1, 0, last)
+		p.x = len(v.lines[p.y]) - 1
+		if p.x < 0 {
+			p.x = 0
+		}
+		return p, vimInclusive, true
+	case "gg", "G", "_":
+		switch {
+		case name == "_":
+			p.y += n - 1
+		case count > 0:
+			p.y = count - 1
+		case name == "gg":
+			p.y = 0
+		default:
+			p.y = last
+		}
+		p.y = clampInt(p.y, 0, last)
+		p.x = firstNonBlank(v.lines[p.y])
+		return p, vimLinewise, true
+	default:
+		return p, vimExclusive, false
+	}
+	return p, vimExclusive, true
+}
+
+// wantedCell returns the cell of line the vertical motions move to.
+func (e *VimEditor) wantedCell(line []cell) int {
+	if e.want < 0 || e.want >= len(line) {
+		if len(line) == 0 {
+			return 0
+		}
+		return len(line) - 1
+	}
+	return e.want
+}
+
+// commandRange returns the range of text an operator operates on.
+func (e *VimEditor) commandRange(v *View, cmd vimCommand) (vimRange, bool) {
+	if len(v.lines) == 0 {
+		return vimRange{}, false
+	}
+	if len(cmd.name) == 2 && (cmd.name[0] == 'i' || cmd.name[0] == 'a') {
+		return e.textObject(v, cmd.name)
+	}
+
+	start := vimPos{v.cx, v.cy}
+	name := cmd.name
+	if cmd.op == 'c' && name == "w" && vimClass(v.lines, start) != 0 {
+		// cw changes to the end of the word, like ce
+		name = "e"
+	}
+	end, kind, ok := e.motion(v, name, cmd.count)
+	if !ok {
+		return vimRange{}, false
+	}
+	if end.less(start) {
+		start, end = end, start
+	}
+
+	switch kind {
+	case vimLinewise:
+		return vimRange{start: start, end: end, lines: true}, true
+	case vimInclusive:
+		if end.x < len(v.lines[end.y]) {
+			end.x++
+		}
+	default:
+		// an exclusive motion to the start of a line ends at the end of
+		// the previous line, so that dw doesn't join the lines
+		if end.x == 0 && end.y > start.y {
+			end = vimPos{len(v.lines[end.y-1]), end.y - 1}
+		}
+	}
+	return vimRange{start: start, end: end}, true
+}
+
+// textObject returns the range of a text object at the cursor, e.g. "iw".
+func (e *VimEditor) textObject(v *View, name string) (vimRange, bool) {
+	p := vimPos{v.cx, v.cy}
+	if p.y >= len(v.lines) {
+		return vimRange{}, false
+	}
+	line := v.lines[p.y]
+	inner := name[0] == 'i'
+
+	switch obj := rune(name[1]); obj {
+	case 'w':
+		if p.x >= len(line) {
+			return vimRange{}, false
+		}
+		start, end := vimRun(line, p.x)
+		if !inner {
+			// a word includes the blanks after it, or before it if there
+			// are none
+			switch {
+			case vimCellClass(line[start]) == 0 && end < len(line):
+				_, end = vimRun(line, end)
+			case end < len(line) && vimCellClass(line[end]) == 0:
+				_, end = vimRun(line, end)
+			case start > 0 && vimCellClass(line[start-1]) == 0:
+				start, _ = vimRun(line, start-1)
+			}
+		}
+		return vimRange{start: vimPos{start, p.y}, end: vimPos{end, p.y}}, true
+	case '"', '\'', '`':
+		var quotes []int
+		for x, c := range line {
+			if c.chr == obj {
+				quotes = append(quotes, x)
+			}
+		}
+		for i := 0; i+1 < len(quotes); i += 2 {
+			start, end := quotes[i], quotes[i+1]
+			if end < p.x {
+				continue
+			}
+			if inner {
+				return vimRange{start: vimPos{start + 1, p.y}, end: vimPos{end, p.y}}, true
+			}
+			end++
+			for end < len(line) && vimCellClass(line[end]) == 0 {
+				end++
+			}
+			return vimRange{start: vimPos{start, p.y}, end: vimPos{end, p.y}}, true
+		}
+		return vimRange{}, false
+	default:
+		closing := vimBrackets[obj]
+		opening := map[rune]rune{')': '(', ']': '[', '}': '{', '>': '<'}[closing]
+		start, ok := vimFindOpening(v.lines, p, opening, closing)
+		if !ok {
+			return vimRange{}, false
+		}
+		end, ok := vimFindClosing(v.lines, start, opening, closing)
+		if !ok {
+			return vimRange{}, false
+		}
+		if inner {
+			start, _ = vimNext(v.lines, start)
+		} else {
+			end.x++
+		}
+		return vimRange{start: start, end: end}, true
+	}
+}
+
+// operate runs an operator on a range of text.
+func (e *VimEditor) operate(v *View, op rune, register rune, r vimRange) {
+	if !r.lines && r.start == r.end {
+		return
+	}
+	e.store(register, vimRegister{vimRangeText(v.lines, r), r.lines}, op == 'y')
+
+	switch op {
+	case 'y':
+		if r.lines {
+			e.setCursor(v, v.cx, r.start.y)
+		} else {
+			e.setCursor(v, r.start.x, r.start.y)
+		}
+	case 'd':
+		e.deleteRange(v, r)
+		if r.lines {
+			y := clampInt(r.start.y, 0, len(v.lines)-1)
+			e.setCursor(v, firstNonBlank(e.line(v, y)), y)
+		} else {
+			e.setCursor(v, r.start.x, r.start.y)
+		}
+	case 'c':
+		e.deleteRange(v, r)
+		if r.lines {
+			// the lines are replaced by an empty line
+			y := r.start.y
+			v.lines = append(v.lines[:y:y], append([][]cell{nil}, v.lines[y:]...)...)
+			e.setCursor(v, 0, y)
+		} else {
+			e.setCursor(v, r.start.x, r.start.y)
+		}
+		e.setMode(v, VimInsert)
+	}
+}
+
+// store stores text deleted or yanked in a register.
+func (e *VimEditor) store(name rune, reg vimRegister, yank bool) {
+	if e.registers == nil {
+		e.registers = make(map[rune]vimRegister)
+	}
+	switch {
+	case name == '_':
+		return
+	case name >= 'A' && name <= 'Z':
+		name = unicode.ToLower(name)
+		if old, ok := e.registers[name]; ok {
+			if old.lines || reg.lines {
+				reg = vimRegister{old.text + "\n" + reg.text, true}
+			} else {
+				reg.text = old.text + reg.text
+			}
+		}
+		e.registers[name] = reg
+	case name >= 'a' && name <= 'z':
+		e.registers[name] = reg
+	case yank:
+		e.registers['0'] = reg
+	}
+	e.registers['"'] = reg
+}
+
+// put puts the text of a register count times after the cursor, or before
+// it.
+func (e *VimEditor) put(v *View, name rune, count int, before bool) {
+	if name == 0 {
+		name = '"'
+	}
+	reg, ok := e.registers[unicode.ToLower(name)]
+	if !ok {
+		return
+	}
+	e.ensureLine(v)
+
+	if v.SingleLine {
+		reg = vimRegister{text: strings.Replace(reg.text, "\n", " ", -1)}
+	}
+	if reg.lines {
+		text := strings.TrimSuffix(strings.Repeat(reg.text+"\n", count), "\n")
+		var lines [][]cell
+		for _, s := range strings.Split(text, "\n") {
+			lines = append(lines, textCells(s, v.FgColor, v.BgColor))
+		}
+		y := v.cy
+		if !before {
+			y++
+		}
+		v.lines = append(v.lines[:y:y], append(lines, v.lines[y:]...)...)
+		v.tainted = true
+		e.setCursor(v, firstNonBlank(v.lines[y]), y)
+		return
+	}
+
+	x := v.cx
+	if !before && x < len(v.lines[v.cy]) {
+		x++
+	}
+	var end vimPos
+	end.x, end.y = v.insertText(x, v.cy, strings.Repeat(reg.text, count))
+	end, _ = vimPrev(v.lines, end)
+	e.setCursor(v, end.x, end.y)
+}
+
+// deleteRange deletes a range of text.
+func (e *VimEditor) deleteRange(v *View, r vimRange) {
+	lines := v.lines
+	if r.lines {
+		v.lines = append(lines[:r.start.y:r.start.y], lines[r.end.y+1:]...)
+	} else {
+		line := append(append([]cell{}, lines[r.start.y][:r.start.x]...), lines[r.end.y][r.end.x:]...)
+		v.lines = append(append(lines[:r.start.y:r.start.y], line), lines[r.end.y+1:]...)
+	}
+	v.tainted = true
+}
+
+// ensureLine adds an empty line to an empty buffer.
+func (e *VimEditor) ensureLine(v *View) {
+	if len(v.lines) == 0 {
+		v.lines = [][]cell{nil}
+		v.cx, v.cy = 0, 0
+	}
+}
+
+// line returns the line y of the buffer, nil if there is none.
+func (e *VimEditor) line(v *View, y int) []cell {
+	if y < 0 || y >= len(v.lines) {
+		return nil
+	}
+	return v.lines[y]
+}
+
+// setCursor moves the cursor to the cell x of the line y, or to the end of
+// the line.
+func (e *VimEditor) setCursor(v *View, x, y int) {
+	if len(v.lines) == 0 {
+		v.cx, v.cy = 0, 0
+		return
+	}
+	y = clampInt(y, 0, len(v.lines)-1)
+	v.cx, v.cy = clampInt(x, 0, len(v.lines[y])), y
+	v.MoveCursor(0, 0)
+}
+
+// clampCursor moves the cursor on the last cell of the line if it is after
+// it, as in normal mode the cursor is on a cell.
+func (e *VimEditor) clampCursor(v *View) {
+	line := e.line(v, v.cy)
+	if len(line) > 0 && v.cx >= len(line) {
+		e.setCursor(v, len(line)-1, v.cy)
+	}
+}
+
+// vimRangeText returns the text of a range.
+func vimRangeText(lines [][]cell, r vimRange) string {
+	var texts []string
+	for y := r.start.y; y <= r.end.y && y < len(lines); y++ {
+		line := lines[y]
+		x0, x1 := 0, len(line)
+		if !r.lines && y == r.start.y {
+			x0 = r.start.x
+		}
+		if !r.lines && y == r.end.y {
+			x1 = r.end.x
+		}
+		texts = append(texts, lineType(line[x0:x1]).String())
+	}
+	return strings.Join(texts, "\n")
+}
+
+// vimCellClass returns the class of a cell for the word motions: 0 for the
+// blanks, 1 for the punctuation and 2 for the word characters.
+func vimCellClass(c cell) int {
+	switch r := c.chr; {
+	case r == 0 || unicode.IsSpace(r):
+		return 0
+	case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+		return 2
+	}
+	return 1
+}
+
+// vimClass returns the class of the cell at p, 0 for the end of a line.
+func vimClass(lines [][]cell, p vimPos) int {
+	if p.y >= len(lines) || p.x >= len(lines[p.y]) {
+		return 0
+	}
+	return vimCellClass(lines[p.y][p.x])
+}
+
+// vimRun returns the run of cells of line of the same class as the cell x,
+// from start to end excluded.
+func vimRun(line []cell, x int) (start, end int) {
+	class := vimCellClass(line[x])
+	start, end = x, x+1
+	for start > 0 && vimCellClass(line[start-1]) == class {
+		start--
+	}
+	for end < len(line) && vimCellClass(line[end]) == class {
+		end++
+	}
+	return start, end
+}
+
+// vimNext returns the position after p, and false at the end of the buffer.
+func vimNext(lines [][]cell, p vimPos) (vimPos, bool) {
+	if p.y < len(lines) && p.x < len(lines[p.y]) {
+		return vimPos{p.x + 1, p.y}, true
+	}
+	if p.y+1 < len(lines) {
+		return vimPos{0, p.y + 1}, true
+	}
+	return p, false
+}
+
+// vimPrev returns the position before p, and false at the start of the
+// buffer.
+func vimPrev(lines [][]cell, p vimPos) (vimPos, bool) {
+	if p.x > 0 {
+		return vimPos{p.x - 1, p.y}, true
+	}
+	if p.y > 0 {
+		return vimPos{len(lines[p.y-1]), p.y - 1}, true
+	}
+	return p, false
+}
+
+// vimWordForward returns the start of the next word, an empty line being a
+// word.
+func vimWordForward(lines [][]cell, p vimPos) vimPos {
+	start, ok := p, true
+	if class := vimClass(lines, p); class != 0 {
+		for ok && vimClass(lines, p) == class {
+			p, ok = vimNext(lines, p)
+		}
+	}
+	for ok && vimClass(lines, p) == 0 {
+		if p != start && p.x == 0 && len(lines[p.y]) == 0 {
+			break
+		}
+		p, ok = vimNext(lines, p)
+	}
+	return p
+}
+
+// vimWordBackward returns the start of the word before p.
+func vimWordBackward(lines [][]cell, p vimPos) vimPos {
+	p, ok := vimPrev(lines, p)
+	for ok && vimClass(lines, p) == 0 {
+		if p.x == 0 && len(lines[p.y]) == 0 {
+			return p
+		}
+		p, ok = vimPrev(lines, p)
+	}
+	class := vimClass(lines, p)
+	for {
+		q, ok := vimPrev(lines, p)
+		if !ok || vimClass(lines, q) != class {
+			return p
+		}
+		p = q
+	}
+}
+
+// vimWordEnd returns the end of the word after p.
+func vimWordEnd(lines [][]cell, p vimPos) vimPos {
+	p, ok := vimNext(lines, p)
+	for ok && vimClass(lines, p) == 0 {
+		p, ok = vimNext(lines, p)
+	}
+	class := vimClass(lines, p)
+	for {
+		q, ok := vimNext(lines, p)
+		if !ok || vimClass(lines, q) != class {
+			return p
+		}
+		p = q
+	}
+}
+
+// vimFindOpening returns the opening bracket enclosing p, or at p.
+func vimFindOpening(lines [][]cell, p vimPos, opening, closing rune) (vimPos, bool) {
+	ok, depth := true, 0
+	if vimRuneAt(lines, p) == closing {
+		p, ok = vimPrev(lines, p)
+	}
+	for ok {
+		switch vimRuneAt(lines, p) {
+		case opening:
+			if depth == 0 {
+				return p, true
+			}
+			depth--
+		case closing:
+			depth++
+		}
+		p, ok = vimPrev(lines, p)
+	}
+	return p, false
+}
+
+// vimFindClosing returns the closing bracket matching the opening bracket
+// at p.
+func vimFindClosing(lines [][]cell, p vimPos, opening, closing rune) (vimPos, bool) {
+	depth := 0
+	p, ok := vimNext(lines, p)
+	for ok {
+		switch vimRuneAt(lines, p) {
+		case closing:
+			if depth == 0 {
+				return p, true
+			}
+			depth--
+		case opening:
+			depth++
+		}
+		p, ok = vimNext(lines, p)
+	}
+	return p, false
+}
+
+// vimRuneAt returns the rune at p, 0 for the end of a line.
+func vimRuneAt(lines [][]cell, p vimPos) rune {
+	if p.y >= len(lines) || p.x >= len(lines[p.y]) {
+		return 0
+	}
+	return lines[p.y][p.x].chr
+}
+
+// firstNonBlank returns the index of the first cell of line which is not
+// blank, or the end of the line.
+func firstNonBlank(line []cell) int {
+	for x, c := range line {
+		if vimCellClass(c) != 0 {
+			return x
+		}
+	}
+	if len(line) > 0 {
+		return len(line) - 1
+	}
+	return 0
+}
+
+// clampInt returns x restricted to the range from min to max.
+func clampInt(x, min, max int) int {
+	if x > max {
+		x = max
+	}
+	if x < min {
+		x = min
+	}
+	return x
+}
@@ -0,0 +1,143 @@
+// Copyright 2021 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"strings"
+	"testing"
+)
+
+// vimType sends keys to a vim editor, \x1b being Esc.
+func vimType(e *VimEditor, v *View, keys string) {
+	for _, r := range keys {
+		switch r {
+		case '\x1b':
+			e.Edit(v, KeyEsc, 0, ModNone)
+		case ' ':
+			e.Edit(v, KeySpace, 0, ModNone)
+		default:
+			e.Edit(v, 0, r, ModNone)
+		}
+	}
+}
+
+func TestVimEditor(t *testing.T) {
+	tests := []struct {
+		text   string
+		x, y   int
+		keys   string
+		want   string
+		wx, wy int
+	}{
+		// motions
+		{"foo bar.baz qux", 0, 0, "3w", "foo bar.baz qux", 8, 0},
+		{"foo bar.baz qux", 0, 0, "$b", "foo bar.baz qux", 12, 0},
+		{"foo bar.baz qux", 0, 0, "ee", "foo bar.baz qux", 6, 0},
+		{"one\n\ntwo\nthree", 0, 0, "w", "one\n\ntwo\nthree", 0, 1},
+		{"one\ntwo\nthree", 0, 0, "G", "one\ntwo\nthree", 0, 2},
+		{"one\ntwo\nthree", 0, 2, "2gg", "one\ntwo\nthree", 0, 1},
+		{"a long line\nab\nanother line", 8, 0, "jj", "a long line\nab\nanother line", 8, 2},
+		{"  indented", 8, 0, "0^", "  indented", 2, 0},
+
+		// operators
+		{"foo bar baz", 4, 0, "dw", "foo baz", 4, 0},
+		{"foo bar baz", 0, 0, "d2w", "baz", 0, 0},
+		{"foo bar\nbaz", 4, 0, "dw", "foo \nbaz", 3, 0},
+		{"foo bar baz", 4, 0, "cwqux\x1b", "foo qux baz", 6, 0},
+		{"foo bar baz", 4, 0, "D", "foo ", 3, 0},
+		{"one\ntwo\nthree", 0, 0, "2dd", "three", 0, 0},
+		{"one\ntwo\nthree", 1, 1, "ccnew\x1b", "one\nnew\nthree", 2, 1},
+		{"abc", 0, 0, "2x", "c", 0, 0},
+
+		// text objects
+		{"call(foo, bar) end", 6, 0, "di(", "call() end", 5, 0},
+		{"call(foo, bar) end", 6, 0, "da(", "call end", 4, 0},
+		{`say "hello world" now`, 7, 0, `ci"bye` + "\x1b", `say "bye" now`, 7, 0},
+		{"foo bar baz", 5, 0, "daw", "foo baz", 4, 0},
+		{"{\n  a\n}", 2, 1, "di{", "{}", 1, 0},
+
+		// registers and put
+		{"foo bar", 0, 0, "ywP", "foo foo bar", 3, 0},
+		{"one\ntwo", 0, 0, "yyjp", "one\ntwo\none", 0, 2},
+		{"foo bar", 0, 0, `"adw"_dw"ap`, "foo ", 3, 0},
+		{"foo bar", 0, 0, `"_dwp`, "bar", 0, 0},
+
+		// insert mode
+		{"bar", 0, 0, "Afoo\x1b", "barfoo", 5, 0},
+		{"bar", 0, 0, "ofoo\x1b", "bar\nfoo", 2, 1},
+		{"bar", 1, 0, "rx", "bxr", 1, 0},
+
+		// visual mode
+		{"foo bar baz", 4, 0, "vey", "foo bar baz", 4, 0},
+		{"foo bar baz", 4, 0, "vlld", "foo  baz", 4, 0},
+		{"one\ntwo\nthree", 1, 0, "Vjd", "three", 0, 0},
+		{"foo bar baz", 0, 0, "wviwc-\x1b", "foo - baz", 4, 0},
+	}
+
+	for _, test := range tests {
+		v := (&Gui{}).newView("v", 0, 0, 40, 10, OutputNormal)
+		v.lines = nil
+		for _, line := range strings.Split(test.text, "\n") {
+			v.lines = append(v.lines, textCells(line, ColorDefault, ColorDefault))
+		}
+		v.cx, v.cy = test.x, test.y
+		e := NewVimEditor()
+
+		vimType(e, v, test.keys)
+		if got := v.Buffer(); got != test.want {
+			t.Errorf("%q on %q: expected %q, got %q", test.keys, test.text, test.want, got)
+		}
+		if x, y := v.Cursor(); x != test.wx || y != test.wy {
+			t.Errorf("%q on %q: expected the cursor at %d,%d, got %d,%d", test.keys, test.text, test.wx, test.wy, x, y)
+		}
+		if e.Mode() != VimNormal {
+			t.Errorf("%q on %q: expected the normal mode, got %s", test.keys, test.text, e.Mode())
+		}
+	}
+}
+
+func TestVimEditorModes(t *testing.T) {
+	v := (&Gui{}).newView("v", 0, 0, 40, 10, OutputNormal)
+	e := NewVimEditor()
+	var modes []string
+	e.OnModeChange = func(v *View, mode VimMode) {
+		modes = append(modes, mode.String())
+	}
+
+	vimType(e, v, "ifoo bar\x1b")
+	if got := v.Buffer(); got != "foo bar" {
+		t.Errorf("expected %q, got %q", "foo bar", got)
+	}
+	vimType(e, v, "0vl")
+	if v.selection == nil || !v.selection.contains(1, 0) || v.selection.contains(2, 0) {
+		t.Errorf("expected the first two cells to be selected, got %+v", v.selection)
+	}
+	vimType(e, v, "y")
+	if v.selection != nil {
+		t.Error("expected the selection to be cleared")
+	}
+	if got := e.Register('"'); got != "fo" {
+		t.Errorf("expected %q to be yanked, got %q", "fo", got)
+	}
+	vimType(e, v, "Vyv\x1b")
+
+	want := []string{"INSERT", "NORMAL", "VISUAL", "NORMAL", "VISUAL LINE", "NORMAL", "VISUAL", "NORMAL"}
+	if !equalStrings(modes, want) {
+		t.Errorf("expected the modes %q, got %q", want, modes)
+	}
+	if got := e.Register('0'); got != "foo bar\n" {
+		t.Errorf("expected the line to be yanked, got %q", got)
+	}
+
+	// the text pasted in normal mode is inserted before the cursor
+	vimType(e, v, "0")
+	e.Paste(v, "x\ny")
+	if got := v.Buffer(); got != "x\nyfoo bar" {
+		t.Errorf("expected %q, got %q", "x\nyfoo bar", got)
+	}
+	if x, y := v.Cursor(); x != 0 || y != 1 {
+		t.Errorf("expected the cursor on the last pasted character, got %d,%d", x, y)
+	}
+}